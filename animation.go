@@ -8,40 +8,50 @@ package assimp
 */
 import "C"
 import (
+	"slices"
 	"unsafe"
 
 	"github.com/flywave/go3d/quaternion"
 	"github.com/flywave/go3d/vec3"
 )
 
-type VectorKey C.struct_aiVectorKey
+type VectorKey struct {
+	time  float64
+	value vec3.T
+}
 
 func (v VectorKey) Time() float64 {
-	return float64(v.mTime)
+	return v.time
 }
 
 func (v VectorKey) Value() vec3.T {
-	return vec3.T{float32(v.mValue.x), float32(v.mValue.y), float32(v.mValue.z)}
+	return v.value
 }
 
-type QuatKey C.struct_aiQuatKey
+type QuatKey struct {
+	time  float64
+	value quaternion.T
+}
 
 func (v QuatKey) Time() float64 {
-	return float64(v.mTime)
+	return v.time
 }
 
 func (v QuatKey) Value() quaternion.T {
-	return quaternion.T{float32(v.mValue.x), float32(v.mValue.y), float32(v.mValue.z), float32(v.mValue.w)}
+	return v.value
 }
 
-type MeshKey C.struct_aiMeshKey
+type MeshKey struct {
+	time  float64
+	value int
+}
 
 func (v MeshKey) Time() float64 {
-	return float64(v.mTime)
+	return v.time
 }
 
 func (v MeshKey) Value() int {
-	return int(v.mValue)
+	return v.value
 }
 
 type AnimBehaviour C.enum_aiAnimBehaviour
@@ -53,120 +63,217 @@ const (
 	AnimBehaviour_Repeat   AnimBehaviour = C.aiAnimBehaviour_REPEAT
 )
 
-type NodeAnim C.struct_aiNodeAnim
+type NodeAnim struct {
+	name         string
+	positionKeys []VectorKey
+	rotationKeys []QuatKey
+	scalingKeys  []VectorKey
+	preState     AnimBehaviour
+	postState    AnimBehaviour
+}
 
 func (v *NodeAnim) Name() string {
-	return C.GoString(&v.mNodeName.data[0])
+	return v.name
 }
 
 func (v *NodeAnim) NumPositionKeys() int {
-	return int(v.mNumPositionKeys)
+	return len(v.positionKeys)
 }
 
 func (v *NodeAnim) PositionKeys() []VectorKey {
-	if v.mNumPositionKeys > 0 && v.mPositionKeys != nil {
-		return unsafe.Slice((*VectorKey)(unsafe.Pointer(v.mPositionKeys)), int(v.mNumPositionKeys))
-	} else {
-		return nil
-	}
+	return v.positionKeys
 }
 
 func (v *NodeAnim) NumRotationKeys() int {
-	return int(v.mNumRotationKeys)
+	return len(v.rotationKeys)
 }
 
 func (v *NodeAnim) RotationKeys() []QuatKey {
-	if v.mNumRotationKeys > 0 && v.mRotationKeys != nil {
-		return unsafe.Slice((*QuatKey)(unsafe.Pointer(v.mRotationKeys)), int(v.mNumRotationKeys))
-	} else {
-		return nil
-	}
+	return v.rotationKeys
 }
 
 func (v *NodeAnim) NumScalingKeys() int {
-	return int(v.mNumScalingKeys)
+	return len(v.scalingKeys)
 }
 
 func (v *NodeAnim) ScalingKeys() []VectorKey {
-	if v.mNumScalingKeys > 0 && v.mScalingKeys != nil {
-		return unsafe.Slice((*VectorKey)(unsafe.Pointer(v.mScalingKeys)), int(v.mNumScalingKeys))
-	} else {
-		return nil
-	}
+	return v.scalingKeys
 }
 
 func (v *NodeAnim) PreState() AnimBehaviour {
-	return AnimBehaviour(v.mPreState)
+	return v.preState
 }
 
 func (v *NodeAnim) PostState() AnimBehaviour {
-	return AnimBehaviour(v.mPostState)
+	return v.postState
 }
 
-type MeshAnim C.struct_aiMeshAnim
+type MeshAnim struct {
+	name string
+	keys []MeshKey
+}
 
 func (v *MeshAnim) Name() string {
-	return C.GoString(&v.mName.data[0])
+	return v.name
 }
 
 func (v *MeshAnim) NumKeys() int {
-	return int(v.mNumKeys)
+	return len(v.keys)
 }
 
 func (v *MeshAnim) Keys() []MeshKey {
-	if v.mNumKeys > 0 && v.mKeys != nil {
-		return unsafe.Slice((*MeshKey)(unsafe.Pointer(v.mKeys)), int(v.mNumKeys))
-	} else {
-		return nil
-	}
+	return v.keys
 }
 
 type Animation struct {
-	a *C.struct_aiAnimation
+	name           string
+	duration       float64
+	ticksPerSecond float64
+	channels       []*NodeAnim
+	meshChannels   []*MeshAnim
 }
 
 func (v *Animation) Name() string {
-	return C.GoString(&v.a.mName.data[0])
+	return v.name
 }
 
 func (v *Animation) Duration() float64 {
-	return float64(v.a.mDuration)
+	return v.duration
 }
 
 func (v *Animation) TicksPerSecond() float64 {
-	return float64(v.a.mTicksPerSecond)
+	return v.ticksPerSecond
 }
 
 func (v *Animation) NumChannels() int {
-	return int(v.a.mNumChannels)
+	return len(v.channels)
 }
 
 func (v *Animation) Channels() []*NodeAnim {
-	if v.a.mNumChannels > 0 && v.a.mChannels != nil {
-		channels := unsafe.Slice(v.a.mChannels, int(v.a.mNumChannels))
-		result := make([]*NodeAnim, len(channels))
-		for i := range channels {
-			result[i] = (*NodeAnim)(unsafe.Pointer(&channels[i]))
-		}
-		return result
-	} else {
-		return nil
-	}
+	return v.channels
 }
 
 func (v *Animation) NumMeshChannels() int {
-	return int(v.a.mNumMeshChannels)
+	return len(v.meshChannels)
 }
 
 func (v *Animation) MeshChannels() []*MeshAnim {
-	if v.a.mNumMeshChannels > 0 && v.a.mMeshChannels != nil {
-		channels := unsafe.Slice(v.a.mMeshChannels, int(v.a.mNumMeshChannels))
-		result := make([]*MeshAnim, len(channels))
-		for i := range channels {
-			result[i] = (*MeshAnim)(unsafe.Pointer(&channels[i]))
+	return v.meshChannels
+}
+
+// clone returns a deep copy of v, so its keys can be changed without
+// affecting v.
+func (v *Animation) clone() *Animation {
+
+	a := *v
+
+	a.channels = make([]*NodeAnim, len(v.channels))
+	for i, c := range v.channels {
+		cc := *c
+		cc.positionKeys = slices.Clone(c.positionKeys)
+		cc.rotationKeys = slices.Clone(c.rotationKeys)
+		cc.scalingKeys = slices.Clone(c.scalingKeys)
+		a.channels[i] = &cc
+	}
+
+	a.meshChannels = make([]*MeshAnim, len(v.meshChannels))
+	for i, c := range v.meshChannels {
+		cc := *c
+		cc.keys = slices.Clone(c.keys)
+		a.meshChannels[i] = &cc
+	}
+
+	return &a
+}
+
+func parseAnimation(ca *C.struct_aiAnimation) *Animation {
+
+	a := &Animation{
+		name:           parseAiString(ca.mName),
+		duration:       float64(ca.mDuration),
+		ticksPerSecond: float64(ca.mTicksPerSecond),
+	}
+
+	if ca.mChannels != nil {
+		cChannels := unsafe.Slice(ca.mChannels, int(ca.mNumChannels))
+		a.channels = make([]*NodeAnim, len(cChannels))
+		for i, cc := range cChannels {
+			a.channels[i] = &NodeAnim{
+				name:         parseAiString(cc.mNodeName),
+				positionKeys: parseVectorKeys(cc.mPositionKeys, uint(cc.mNumPositionKeys)),
+				rotationKeys: parseQuatKeys(cc.mRotationKeys, uint(cc.mNumRotationKeys)),
+				scalingKeys:  parseVectorKeys(cc.mScalingKeys, uint(cc.mNumScalingKeys)),
+				preState:     AnimBehaviour(cc.mPreState),
+				postState:    AnimBehaviour(cc.mPostState),
+			}
 		}
-		return result
-	} else {
+	}
+
+	if ca.mMeshChannels != nil {
+		cChannels := unsafe.Slice(ca.mMeshChannels, int(ca.mNumMeshChannels))
+		a.meshChannels = make([]*MeshAnim, len(cChannels))
+		for i, cc := range cChannels {
+			a.meshChannels[i] = &MeshAnim{
+				name: parseAiString(cc.mName),
+				keys: parseMeshKeys(cc.mKeys, uint(cc.mNumKeys)),
+			}
+		}
+	}
+
+	return a
+}
+
+func parseVectorKeys(ck *C.struct_aiVectorKey, count uint) []VectorKey {
+	if ck == nil || count == 0 {
+		return nil
+	}
+
+	cKeys := unsafe.Slice(ck, int(count))
+	keys := make([]VectorKey, count)
+
+	for i := range cKeys {
+		keys[i] = VectorKey{
+			time:  float64(cKeys[i].mTime),
+			value: parseVec3(&cKeys[i].mValue),
+		}
+	}
+
+	return keys
+}
+
+func parseQuatKeys(ck *C.struct_aiQuatKey, count uint) []QuatKey {
+	if ck == nil || count == 0 {
 		return nil
 	}
+
+	cKeys := unsafe.Slice(ck, int(count))
+	keys := make([]QuatKey, count)
+
+	for i := range cKeys {
+		q := cKeys[i].mValue
+		keys[i] = QuatKey{
+			time:  float64(cKeys[i].mTime),
+			value: quaternion.T{float32(q.x), float32(q.y), float32(q.z), float32(q.w)},
+		}
+	}
+
+	return keys
+}
+
+func parseMeshKeys(ck *C.struct_aiMeshKey, count uint) []MeshKey {
+	if ck == nil || count == 0 {
+		return nil
+	}
+
+	cKeys := unsafe.Slice(ck, int(count))
+	keys := make([]MeshKey, count)
+
+	for i := range cKeys {
+		keys[i] = MeshKey{
+			time:  float64(cKeys[i].mTime),
+			value: int(cKeys[i].mValue),
+		}
+	}
+
+	return keys
 }
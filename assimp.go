@@ -10,13 +10,19 @@ package assimp
 
 //Functions
 struct aiScene* aiImportFile(const char* pFile, unsigned int pFlags);
+struct aiScene* aiImportFileFromMemory(const char* pBuffer, unsigned int pLength, unsigned int pFlags, const char* pHint);
+struct aiScene* aiApplyPostProcessing(const struct aiScene* pScene, unsigned int pFlags);
 void aiReleaseImport(const struct aiScene* pScene);
-const char* aiGetErrorString();
 unsigned int aiGetMaterialTextureCount(const struct aiMaterial* pMat, enum aiTextureType type);
 */
 import "C"
 import (
 	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"strings"
 	"unsafe"
 
 	"github.com/flywave/go3d/mat4"
@@ -34,7 +40,6 @@ type Node struct {
 }
 
 type EmbeddedTexture struct {
-	cTex         *C.struct_aiTexture
 	Width        uint
 	Height       uint
 	FormatHint   string
@@ -43,6 +48,9 @@ type EmbeddedTexture struct {
 	Filename     string
 }
 
+// Metadata is a metadata value. Value holds a bool, int32, uint64, float32,
+// float64, string, vec3.T or map[string]Metadata depending on Type, or nil
+// for unknown types. See GetMeta.
 type Metadata struct {
 	Type  MetadataType
 	Value interface{}
@@ -52,9 +60,29 @@ type MetadataEntry struct {
 	Data []byte
 }
 
+// Scene is a Go copy of an imported aiScene. Every field, including
+// animations, lights, cameras and materials, is owned by Go and stays valid
+// after the imported data has been released.
+//
+// A Scene returned by an import keeps the imported C data alive until Close
+// is called. Scenes that are garbage collected without Close release it too,
+// but log a leak warning.
 type Scene struct {
-	cScene *C.struct_aiScene
-	Flags  SceneFlag
+	handle  *sceneHandle
+	cleanup runtime.Cleanup
+	closed  bool
+	// opts holds the options the scene was imported with, they apply again
+	// when ApplyPostProcessing re-parses it.
+	opts *ImportOptions
+	// bounds caches the results of Bounds and friends, see InvalidateBounds.
+	bounds *boundsCache
+	// unitScaled is set if PostProcessGlobalScale already applied the unit
+	// of the file, so the FBX UnitScaleFactor no longer describes the data.
+	unitScaled bool
+
+	Flags SceneFlag
+	// Name is the scene name, e.g. of the glTF scene. It is often empty.
+	Name string
 
 	RootNode  *Node
 	Meshes    []*Mesh
@@ -64,10 +92,172 @@ type Scene struct {
 	Animations []*Animation
 	Lights     []*Light
 	Cameras    []*Camera
+
+	// Metadata holds the scene metadata, e.g. the FBX UnitScaleFactor and
+	// UpAxis, see Normalize.
+	Metadata map[string]Metadata
+
+	// Warnings holds the warnings Assimp logged while importing the scene.
+	Warnings []string
+}
+
+var _ io.Closer = (*Scene)(nil)
+
+// sceneHandle owns the imported aiScene. It is kept apart from the Scene so
+// the cleanup registered on the Scene can free it without keeping the Scene
+// reachable.
+type sceneHandle struct {
+	cs *C.struct_aiScene
+	// freeIO frees the aiFileIO of a scene imported with ImportFS. Assimp
+	// keeps using it, e.g. in PostProcessEmbedTextures, until the import is
+	// released.
+	freeIO func()
+}
+
+func (h *sceneHandle) release() bool {
+
+	// Releasing the import may log, so it must not interleave with a
+	// running import.
+	importMu.Lock()
+	defer importMu.Unlock()
+
+	if h.cs == nil {
+		h.releaseIO()
+		return false
+	}
+
+	C.aiReleaseImport(h.cs)
+	h.cs = nil
+	h.releaseIO()
+	return true
+}
+
+// forget drops an aiScene that Assimp has released itself.
+func (h *sceneHandle) forget() {
+
+	importMu.Lock()
+	defer importMu.Unlock()
+
+	h.cs = nil
+	h.releaseIO()
+}
+
+func (h *sceneHandle) releaseIO() {
+	if h.freeIO != nil {
+		h.freeIO()
+		h.freeIO = nil
+	}
+}
+
+// set replaces the imported aiScene under the import lock, so it doesn't race
+// with a release from the cleanup.
+func (h *sceneHandle) set(cs *C.struct_aiScene) {
+
+	importMu.Lock()
+	defer importMu.Unlock()
+
+	h.cs = cs
+}
+
+// releaseLeakedScene is the cleanup of a Scene that was never closed.
+func releaseLeakedScene(h *sceneHandle) {
+
+	if !h.release() {
+		return
+	}
+
+	logger := globalLogger.Load()
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	logger.Warn("assimp: scene garbage collected without Close, imported data released")
+}
+
+// newImportedScene parses cs into a Scene that owns it. opts may be nil.
+func newImportedScene(cs *C.struct_aiScene, session *logSession, opts *ImportOptions, postProcessFlags PostProcess) *Scene {
+
+	s := parseScene(cs, opts)
+	s.Warnings = session.warnings
+	s.opts = opts
+	s.unitScaled = postProcessFlags&PostProcessGlobalScale != 0
+	s.handle = &sceneHandle{cs: cs}
+	s.cleanup = runtime.AddCleanup(s, releaseLeakedScene, s.handle)
+	s.adoptMeshViews()
+
+	return s
+}
+
+// adoptMeshViews ties the zero-copy meshes to s, so the imported data isn't
+// released while they are reachable.
+func (s *Scene) adoptMeshViews() {
+	if s.opts == nil || !s.opts.ZeroCopyMeshes {
+		return
+	}
+
+	for _, m := range s.Meshes {
+		if m != nil {
+			m.owner = s
+		}
+	}
+}
+
+// dropMeshViews clears the zero-copy meshes before the data they point into
+// is released or replaced.
+func (s *Scene) dropMeshViews() {
+	for _, m := range s.Meshes {
+		m.dropViews()
+	}
+}
+
+// cScene returns the imported data, or nil if there is none or it has been
+// released.
+func (s *Scene) cScene() *C.struct_aiScene {
+	if s.handle == nil {
+		return nil
+	}
+
+	return s.handle.cs
 }
 
 func (s *Scene) releaseCResources() {
-	C.aiReleaseImport(s.cScene)
+	if s.handle == nil {
+		return
+	}
+
+	s.cleanup.Stop()
+	s.handle.release()
+}
+
+// Detach releases the imported C data right away. The Go side of the scene
+// doesn't depend on it, so the scene stays usable (e.g. kept in a cache);
+// only ApplyPostProcessing needs the C data. Zero-copy meshes are copied into
+// Go memory first. The release func returned by the import becomes a no-op.
+func (s *Scene) Detach() {
+
+	for _, m := range s.Meshes {
+		m.Detach()
+	}
+
+	s.releaseCResources()
+}
+
+// Close releases the imported C data. The Go fields stay valid, except for
+// the vertex data of zero-copy meshes, which is cleared; slices taken from
+// such meshes must not be used after Close. Methods that need the C data
+// return ErrSceneClosed afterwards. Closing a scene more than once is a
+// no-op. Close implements io.Closer and always returns nil.
+func (s *Scene) Close() error {
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	s.dropMeshViews()
+	s.InvalidateBounds()
+	s.releaseCResources()
+	return nil
 }
 
 func ImportFile(file string, postProcessFlags PostProcess) (s *Scene, release func(), err error) {
@@ -75,72 +265,183 @@ func ImportFile(file string, postProcessFlags PostProcess) (s *Scene, release fu
 	cstr := C.CString(file)
 	defer C.free(unsafe.Pointer(cstr))
 
-	cs := C.aiImportFile(cstr, C.uint(postProcessFlags))
+	cs, session := importLocked(nil, func() *C.struct_aiScene {
+		return C.aiImportFile(cstr, C.uint(postProcessFlags))
+	})
 	if cs == nil {
-		return nil, func() {}, getAiErr()
+		return nil, func() {}, newImportError(file, "", session.errString)
 	}
 
-	s = parseScene(cs)
-	return s, func() { s.releaseCResources() }, nil
+	s = newImportedScene(cs, session, nil, postProcessFlags)
+	return s, func() { s.Close() }, nil
 }
 
-func getAiErr() error {
-	return errors.New("asig error: " + C.GoString(C.aiGetErrorString()))
+// ImportFromMemory imports a scene from an in-memory file buffer. hint is the
+// file extension of the data (e.g. "obj" or ".glb") and helps Assimp pick the
+// right importer; it may be empty for formats that can be detected from content.
+// Formats that reference sibling files (OBJ+MTL, glTF with external buffers)
+// can't resolve those references from a single buffer.
+func ImportFromMemory(data []byte, hint string, postProcessFlags PostProcess) (s *Scene, release func(), err error) {
+
+	if len(data) == 0 {
+		return nil, func() {}, &ImportError{Hint: strings.TrimPrefix(hint, "."), Message: "empty buffer", Err: ErrParseFailed}
+	}
+
+	if uint64(len(data)) > math.MaxUint32 {
+		return nil, func() {}, &ImportError{Hint: strings.TrimPrefix(hint, "."), Message: "buffer too large", Err: ErrParseFailed}
+	}
+
+	chint := C.CString(strings.TrimPrefix(hint, "."))
+	defer C.free(unsafe.Pointer(chint))
+
+	cs, session := importLocked(nil, func() *C.struct_aiScene {
+		return C.aiImportFileFromMemory((*C.char)(unsafe.Pointer(&data[0])), C.uint(len(data)), C.uint(postProcessFlags), chint)
+	})
+	if cs == nil {
+		return nil, func() {}, newImportError("", hint, session.errString)
+	}
+
+	s = newImportedScene(cs, session, nil, postProcessFlags)
+	return s, func() { s.Close() }, nil
+}
+
+// ImportReader reads r to the end and imports the result like ImportFromMemory.
+func ImportReader(r io.Reader, hint string, postProcessFlags PostProcess) (s *Scene, release func(), err error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	return ImportFromMemory(data, hint, postProcessFlags)
+}
+
+// ApplyPostProcessing runs post-processing steps on an already imported scene
+// and re-parses Meshes, Materials, RootNode and the other fields from the
+// modified data, so the scene can be inspected before choosing the steps.
+// If a step fails Assimp releases the underlying data and the scene can't be
+// post-processed any further. Zero-copy meshes parsed before the call are
+// cleared, since post-processing rewrites the data they point into.
+func (s *Scene) ApplyPostProcessing(postProcessFlags PostProcess) error {
+
+	if s.closed {
+		return ErrSceneClosed
+	}
+
+	if s.cScene() == nil {
+		return errors.New("asig error: scene has no imported data")
+	}
+
+	cs, session := importLocked(s.opts, func() *C.struct_aiScene {
+		return C.aiApplyPostProcessing(s.cScene(), C.uint(postProcessFlags))
+	})
+	// The steps modify the meshes in place.
+	s.dropMeshViews()
+
+	if cs == nil {
+		s.cleanup.Stop()
+		s.handle.forget()
+
+		// aiApplyPostProcessing doesn't update aiGetErrorString, the
+		// failing step only logs its error.
+		msg := session.lastError
+		if msg == "" {
+			msg = "post-processing failed"
+		}
+
+		return &ImportError{Message: msg, Err: classifyImportError(msg)}
+	}
+
+	parsed := parseScene(cs, s.opts)
+	parsed.Warnings = append(s.Warnings, session.warnings...)
+	parsed.opts = s.opts
+	parsed.unitScaled = s.unitScaled || postProcessFlags&PostProcessGlobalScale != 0
+	parsed.handle, parsed.cleanup = s.handle, s.cleanup
+	parsed.handle.set(cs)
+
+	*s = *parsed
+	s.InvalidateBounds()
+	s.adoptMeshViews()
+	return nil
 }
 
-func parseScene(cs *C.struct_aiScene) *Scene {
+// parseScene copies cs into a new Scene. opts may be nil.
+func parseScene(cs *C.struct_aiScene, opts *ImportOptions) *Scene {
+
+	var o ImportOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	workers := o.workers()
 
-	s := &Scene{cScene: cs}
+	s := &Scene{
+		Textures:   []*EmbeddedTexture{},
+		Animations: []*Animation{},
+		Lights:     []*Light{},
+		Cameras:    []*Camera{},
+	}
 	s.Flags = SceneFlag(cs.mFlags)
+	s.Name = parseAiString(cs.mName)
+	s.Metadata = parseMetadata(cs.mMetaData)
 	s.RootNode = parseRootNode(cs.mRootNode)
-	s.Meshes = parseMeshes(cs.mMeshes, uint(cs.mNumMeshes))
-	s.Materials = parseMaterials(cs.mMaterials, uint(cs.mNumMaterials))
-	s.Textures = parseTextures(cs.mTextures, uint(s.cScene.mNumTextures))
+	s.Meshes = parseMeshes(cs.mMeshes, uint(cs.mNumMeshes), &o)
+	s.Materials = parseMaterials(cs.mMaterials, uint(cs.mNumMaterials), workers)
 
-	s.Animations = parseAnimations(cs.mAnimations, uint(cs.mNumAnimations))
-	s.Lights = parseLights(cs.mLights, uint(s.cScene.mNumLights))
-	s.Cameras = parseCameras(cs.mCameras, uint(s.cScene.mNumCameras))
+	if !o.Skip.Textures {
+		s.Textures = parseTextures(cs.mTextures, uint(cs.mNumTextures), workers)
+	}
+	if !o.Skip.Animations {
+		s.Animations = parseAnimations(cs.mAnimations, uint(cs.mNumAnimations))
+	}
+	if !o.Skip.Lights {
+		s.Lights = parseLights(cs.mLights, uint(cs.mNumLights))
+	}
+	if !o.Skip.Cameras {
+		s.Cameras = parseCameras(cs.mCameras, uint(cs.mNumCameras))
+	}
 
 	return s
 }
 
 func parseRootNode(cNodesIn *C.struct_aiNode) *Node {
 
-	rn := &Node{
-		Name:           parseAiString(cNodesIn.mName),
-		Transformation: parseMat4(&cNodesIn.mTransformation),
-		Parent:         nil,
-		MeshIndicies:   parseUInts(cNodesIn.mMeshes, uint(cNodesIn.mNumMeshes)),
-		Metadata:       parseMetadata(cNodesIn.mMetaData),
+	if cNodesIn == nil {
+		return nil
 	}
 
-	rn.Children = parseNodes(cNodesIn.mChildren, rn, int(cNodesIn.mNumChildren))
-	return rn
+	return parseNode(cNodesIn, nil)
 }
 
-func parseNodes(cNodesIn **C.struct_aiNode, parent *Node, parentChildrenCount int) []*Node {
+func parseNode(cn *C.struct_aiNode, parent *Node) *Node {
 
-	if cNodesIn == nil || parentChildrenCount <= 0 {
-		return []*Node{}
+	n := &Node{
+		Name:           parseAiString(cn.mName),
+		Transformation: parseMat4(&cn.mTransformation),
+		Parent:         parent,
+		MeshIndicies:   parseUInts(cn.mMeshes, uint(cn.mNumMeshes)),
+		Metadata:       parseMetadata(cn.mMetaData),
 	}
 
-	nodes := make([]*Node, parentChildrenCount)
+	n.Children = parseNodes(cn.mChildren, n, int(cn.mNumChildren))
+	return n
+}
 
-	cNodes := unsafe.Slice((*C.struct_aiNode)(unsafe.Pointer(cNodesIn)), parentChildrenCount)
+// parseNodes parses the children of parent. mChildren is an array of
+// pointers, and every child carries its own child count.
+func parseNodes(cNodesIn **C.struct_aiNode, parent *Node, count int) []*Node {
 
-	for i := 0; i < len(nodes); i++ {
+	if cNodesIn == nil || count <= 0 {
+		return []*Node{}
+	}
 
-		n := cNodes[i]
+	nodes := make([]*Node, 0, count)
 
-		nodes[i] = &Node{
-			Name:           parseAiString(n.mName),
-			Transformation: parseMat4(&n.mTransformation),
-			Parent:         parent,
-			MeshIndicies:   parseUInts(n.mMeshes, uint(n.mNumMeshes)),
-			Metadata:       parseMetadata(n.mMetaData),
+	for _, cn := range unsafe.Slice(cNodesIn, count) {
+		if cn == nil {
+			continue
 		}
-
-		nodes[i].Children = parseNodes(n.mChildren, nodes[i], parentChildrenCount)
+		nodes = append(nodes, parseNode(cn, parent))
 	}
 
 	return nodes
@@ -187,12 +488,14 @@ func parseMetadataEntry(cv C.struct_aiMetadataEntry) Metadata {
 		m.Value = parseAiString(*(*C.struct_aiString)(cv.mData))
 	case MetadataTypeVec3:
 		m.Value = parseVec3((*C.struct_aiVector3D)(cv.mData))
+	case MetadataTypeMetadata:
+		m.Value = parseMetadata((*C.struct_aiMetadata)(cv.mData))
 	}
 
 	return m
 }
 
-func parseTextures(cTexIn **C.struct_aiTexture, count uint) []*EmbeddedTexture {
+func parseTextures(cTexIn **C.struct_aiTexture, count uint, workers int) []*EmbeddedTexture {
 
 	if cTexIn == nil {
 		return []*EmbeddedTexture{}
@@ -200,20 +503,20 @@ func parseTextures(cTexIn **C.struct_aiTexture, count uint) []*EmbeddedTexture {
 
 	textures := make([]*EmbeddedTexture, count)
 
-	cTex := unsafe.Slice((*C.struct_aiTexture)(unsafe.Pointer(cTexIn)), int(count))
+	cTex := unsafe.Slice(cTexIn, int(count))
 
-	for i := 0; i < int(count); i++ {
+	parallelFor(int(count), workers, func(i int) {
 
+		t := cTex[i]
 		textures[i] = &EmbeddedTexture{
-			cTex:         &cTex[i],
-			Width:        uint(cTex[i].mWidth),
-			Height:       uint(cTex[i].mHeight),
-			FormatHint:   C.GoString(&cTex[i].achFormatHint[0]),
-			Filename:     parseAiString(cTex[i].mFilename),
-			Data:         parseTexels(cTex[i].pcData, uint(cTex[i].mWidth), uint(cTex[i].mHeight)),
-			IsCompressed: cTex[i].mHeight == 0,
+			Width:        uint(t.mWidth),
+			Height:       uint(t.mHeight),
+			FormatHint:   C.GoString(&t.achFormatHint[0]),
+			Filename:     parseAiString(t.mFilename),
+			Data:         parseTexels(t.pcData, uint(t.mWidth), uint(t.mHeight)),
+			IsCompressed: t.mHeight == 0,
 		}
-	}
+	})
 
 	return textures
 }
@@ -226,13 +529,10 @@ func parseAnimations(cAnim **C.struct_aiAnimation, count uint) []*Animation {
 
 	animations := make([]*Animation, count)
 
-	cAmis := unsafe.Slice((*C.struct_aiAnimation)(unsafe.Pointer(cAnim)), int(count))
+	cAmis := unsafe.Slice(cAnim, int(count))
 
 	for i := 0; i < int(count); i++ {
-
-		animations[i] = &Animation{
-			a: &cAmis[i],
-		}
+		animations[i] = parseAnimation(cAmis[i])
 	}
 
 	return animations
@@ -246,13 +546,10 @@ func parseLights(cLight **C.struct_aiLight, count uint) []*Light {
 
 	lights := make([]*Light, count)
 
-	cLights := unsafe.Slice((*C.struct_aiLight)(unsafe.Pointer(cLight)), int(count))
+	cLights := unsafe.Slice(cLight, int(count))
 
 	for i := 0; i < int(count); i++ {
-
-		lights[i] = &Light{
-			l: &cLights[i],
-		}
+		lights[i] = parseLight(cLights[i])
 	}
 
 	return lights
@@ -265,68 +562,99 @@ func parseCameras(cCamera **C.struct_aiCamera, count uint) []*Camera {
 
 	cams := make([]*Camera, count)
 
-	cCameras := unsafe.Slice((*C.struct_aiCamera)(unsafe.Pointer(cCamera)), int(count))
+	cCameras := unsafe.Slice(cCamera, int(count))
 
 	for i := 0; i < int(count); i++ {
-
-		cams[i] = &Camera{
-			c: &cCameras[i],
-		}
+		cams[i] = parseCamera(cCameras[i])
 	}
 
 	return cams
 }
 
+// parseTexels copies the texel data of a texture. Compressed textures store
+// width bytes of the original file, uncompressed ones width*height BGRA
+// texels; in both cases the bytes are copied unchanged.
 func parseTexels(cTexelsIn *C.struct_aiTexel, width, height uint) []byte {
 
-	isCompressed := height == 0
-
-	texelCount := width
-	if !isCompressed {
-		texelCount *= height
+	if cTexelsIn == nil {
+		return []byte{}
 	}
-	texelCount /= 4
-
-	data := make([]byte, texelCount*4)
 
-	cTexels := unsafe.Slice((*C.struct_aiTexel)(unsafe.Pointer(cTexelsIn)), int(texelCount))
-
-	for i := 0; i < int(texelCount); i++ {
-
-		index := i * 4
-		data[index] = byte(cTexels[i].b)
-		data[index+1] = byte(cTexels[i].g)
-		data[index+2] = byte(cTexels[i].r)
-		data[index+3] = byte(cTexels[i].a)
+	size := width
+	if height != 0 {
+		size = width * height * 4
 	}
 
-	return data
+	return C.GoBytes(unsafe.Pointer(cTexelsIn), C.int(size))
 }
 
-func parseMeshes(cm **C.struct_aiMesh, count uint) []*Mesh {
+func parseMeshes(cm **C.struct_aiMesh, count uint, opts *ImportOptions) []*Mesh {
 	if cm == nil {
 		return []*Mesh{}
 	}
 
 	meshes := make([]*Mesh, count)
 
-	cmeshes := unsafe.Slice((*C.struct_aiMesh)(unsafe.Pointer(cm)), int(count))
+	cmeshes := unsafe.Slice(cm, int(count))
 
-	for i := 0; i < int(count); i++ {
+	// The filter runs on the calling goroutine, in mesh order, so it doesn't
+	// have to be safe for concurrent use.
+	var skip []bool
+	if opts.MeshFilter != nil {
+		skip = make([]bool, count)
+		for i, cmesh := range cmeshes {
+			skip[i] = !opts.MeshFilter(parseAiString(cmesh.mName), uint(cmesh.mMaterialIndex))
+		}
+	}
 
-		m := &Mesh{}
+	parallelFor(int(count), opts.workers(), func(i int) {
 
 		cmesh := cmeshes[i]
+
+		if skip != nil && skip[i] {
+			return
+		}
+
+		m := &Mesh{}
+
 		vertCount := uint(cmesh.mNumVertices)
 
-		m.Vertices = parseVec3s(cmesh.mVertices, vertCount)
-		m.Normals = parseVec3s(cmesh.mNormals, vertCount)
-		m.Tangents = parseVec3s(cmesh.mTangents, vertCount)
-		m.BitTangents = parseVec3s(cmesh.mBitangents, vertCount)
+		if opts.ZeroCopyMeshes {
+			m.Vertices = viewVec3s(cmesh.mVertices, vertCount)
+			m.Normals = viewVec3s(cmesh.mNormals, vertCount)
+			m.Tangents = viewVec3s(cmesh.mTangents, vertCount)
+			m.BitTangents = viewVec3s(cmesh.mBitangents, vertCount)
+
+			for j := 0; j < MaxColorSets; j++ {
+				m.ColorSets[j] = viewColors(cmesh.mColors[j], vertCount)
+			}
+
+			for j := 0; j < MaxTexCoords; j++ {
+				m.TexCoords[j] = viewVec3s(cmesh.mTextureCoords[j], vertCount)
+			}
+
+			m.Indices, m.FaceOffsets = parseFlatFaces(cmesh.mFaces, uint(cmesh.mNumFaces))
+		} else {
+			m.Vertices = parseVec3s(cmesh.mVertices, vertCount)
+			m.Normals = parseVec3s(cmesh.mNormals, vertCount)
+			m.Tangents = parseVec3s(cmesh.mTangents, vertCount)
+			m.BitTangents = parseVec3s(cmesh.mBitangents, vertCount)
+
+			m.ColorSets = parseColorSet(cmesh.mColors, vertCount)
+
+			m.TexCoords = parseTexCoords(cmesh.mTextureCoords, vertCount)
 
-		m.ColorSets = parseColorSet(cmesh.mColors, vertCount)
+			cFaces := unsafe.Slice((*C.struct_aiFace)(unsafe.Pointer(cmesh.mFaces)), int(cmesh.mNumFaces))
+
+			m.Faces = make([]Face, cmesh.mNumFaces)
+			for j := 0; j < len(m.Faces); j++ {
+
+				m.Faces[j] = Face{
+					Indices: parseUInts(cFaces[j].mIndices, uint(cFaces[j].mNumIndices)),
+				}
+			}
+		}
 
-		m.TexCoords = parseTexCoords(cmesh.mTextureCoords, vertCount)
 		m.TexCoordChannelCount = [8]uint{}
 		for j := 0; j < len(cmesh.mTextureCoords); j++ {
 
@@ -334,32 +662,32 @@ func parseMeshes(cm **C.struct_aiMesh, count uint) []*Mesh {
 				continue
 			}
 
-			m.TexCoordChannelCount[j] = uint(cmeshes[j].mNumUVComponents[j])
+			m.TexCoordChannelCount[j] = uint(cmesh.mNumUVComponents[j])
 		}
 
-		cFaces := unsafe.Slice((*C.struct_aiFace)(unsafe.Pointer(cmesh.mFaces)), int(cmesh.mNumFaces))
-
-		m.Faces = make([]Face, cmesh.mNumFaces)
-		for j := 0; j < len(m.Faces); j++ {
+		m.TexCoordNames = parseTexCoordNames(cmesh.mTextureCoordsNames)
 
-			m.Faces[j] = Face{
-				Indices: parseUInts(cFaces[j].mIndices, uint(cFaces[j].mNumIndices)),
-			}
+		m.Bones = []*Bone{}
+		if !opts.Skip.Bones {
+			m.Bones = parseBones(cmesh.mBones, uint(cmesh.mNumBones))
 		}
 
-		m.Bones = parseBones(cmesh.mBones, uint(cmesh.mNumBones))
-		m.AnimMeshes = parseAnimMeshes(cmesh.mAnimMeshes, uint(cmesh.mNumAnimMeshes))
+		m.AnimMeshes = []*AnimMesh{}
+		if !opts.Skip.AnimMeshes {
+			m.AnimMeshes = parseAnimMeshes(cmesh.mAnimMeshes, uint(cmesh.mNumAnimMeshes))
+		}
 		m.AABB = AABB{
 			Min: parseVec3(&cmesh.mAABB.mMin),
 			Max: parseVec3(&cmesh.mAABB.mMax),
 		}
 
+		m.PrimitiveTypes = PrimitiveType(cmesh.mPrimitiveTypes)
 		m.MorphMethod = MorphMethod(cmesh.mMethod)
 		m.MaterialIndex = uint(cmesh.mMaterialIndex)
 		m.Name = parseAiString(cmesh.mName)
 
 		meshes[i] = m
-	}
+	})
 
 	return meshes
 }
@@ -383,7 +711,7 @@ func parseAnimMeshes(cam **C.struct_aiAnimMesh, count uint) []*AnimMesh {
 
 	animMeshes := make([]*AnimMesh, count)
 
-	cAnimMeshes := unsafe.Slice((*C.struct_aiAnimMesh)(unsafe.Pointer(cam)), int(count))
+	cAnimMeshes := unsafe.Slice(cam, int(count))
 
 	for i := 0; i < int(count); i++ {
 
@@ -403,6 +731,24 @@ func parseAnimMeshes(cam **C.struct_aiAnimMesh, count uint) []*AnimMesh {
 	return animMeshes
 }
 
+// parseTexCoordNames reads aiMesh.mTextureCoordsNames, an optional array
+// of MaxTexCoords optional names.
+func parseTexCoordNames(cn **C.struct_aiString) [MaxTexCoords]string {
+	names := [MaxTexCoords]string{}
+
+	if cn == nil {
+		return names
+	}
+
+	for i, n := range unsafe.Slice(cn, MaxTexCoords) {
+		if n != nil {
+			names[i] = parseAiString(*n)
+		}
+	}
+
+	return names
+}
+
 func parseTexCoords(ctc [MaxTexCoords]*C.struct_aiVector3D, vertCount uint) [MaxTexCoords][]vec3.T {
 	texCoords := [MaxTexCoords][]vec3.T{}
 
@@ -440,7 +786,7 @@ func parseBones(cbs **C.struct_aiBone, count uint) []*Bone {
 
 	bones := make([]*Bone, count)
 
-	cbones := unsafe.Slice((*C.struct_aiBone)(unsafe.Pointer(cbs)), int(count))
+	cbones := unsafe.Slice(cbs, int(count))
 
 	for i := 0; i < int(count); i++ {
 
@@ -549,27 +895,90 @@ func parseColors(cv *C.struct_aiColor4D, count uint) []vec4.T {
 	return verts
 }
 
-func parseMaterials(cMatsIn **C.struct_aiMaterial, count uint) []*Material {
+// The zero-copy views reinterpret Assimp's vectors as go3d types, which
+// requires ai_real to be float.
+var (
+	_ [unsafe.Sizeof(C.struct_aiVector3D{}) - unsafe.Sizeof(vec3.T{})]struct{}
+	_ [unsafe.Sizeof(vec3.T{}) - unsafe.Sizeof(C.struct_aiVector3D{})]struct{}
+	_ [unsafe.Sizeof(C.struct_aiColor4D{}) - unsafe.Sizeof(vec4.T{})]struct{}
+	_ [unsafe.Sizeof(vec4.T{}) - unsafe.Sizeof(C.struct_aiColor4D{})]struct{}
+)
+
+// viewVec3s returns a slice over cv without copying, nil if cv is nil.
+func viewVec3s(cv *C.struct_aiVector3D, count uint) []vec3.T {
+	if cv == nil || count == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*vec3.T)(unsafe.Pointer(cv)), int(count))
+}
+
+// viewColors returns a slice over cv without copying, nil if cv is nil.
+func viewColors(cv *C.struct_aiColor4D, count uint) []vec4.T {
+	if cv == nil || count == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*vec4.T)(unsafe.Pointer(cv)), int(count))
+}
+
+// parseFlatFaces copies the face indices into a single buffer. Assimp
+// allocates the indices of every face separately, so they can't be viewed
+// in place.
+func parseFlatFaces(cf *C.struct_aiFace, count uint) (indices []uint32, offsets []uint32) {
+	if cf == nil || count == 0 {
+		return []uint32{}, []uint32{0}
+	}
+
+	cFaces := unsafe.Slice(cf, int(count))
+
+	total := 0
+	for _, f := range cFaces {
+		total += int(f.mNumIndices)
+	}
+
+	indices = make([]uint32, 0, total)
+	offsets = make([]uint32, count+1)
+
+	for i, f := range cFaces {
+		offsets[i] = uint32(len(indices))
+		if f.mIndices != nil {
+			indices = append(indices, unsafe.Slice((*uint32)(unsafe.Pointer(f.mIndices)), int(f.mNumIndices))...)
+		}
+	}
+	offsets[count] = uint32(len(indices))
+
+	return indices, offsets
+}
+
+func parseMaterials(cMatsIn **C.struct_aiMaterial, count uint, workers int) []*Material {
+	if cMatsIn == nil {
+		return []*Material{}
+	}
+
 	mats := make([]*Material, count)
 
-	cMats := unsafe.Slice((*C.struct_aiMaterial)(unsafe.Pointer(cMatsIn)), int(count))
+	cMats := unsafe.Slice(cMatsIn, int(count))
 
-	for i := 0; i < int(count); i++ {
+	parallelFor(int(count), workers, func(i int) {
 
 		mats[i] = &Material{
-			cMat:             &cMats[i],
 			Properties:       parseMatProperties(cMats[i].mProperties, uint(cMats[i].mNumProperties)),
 			AllocatedStorage: uint(cMats[i].mNumAllocated),
 		}
-	}
+	})
 
 	return mats
 }
 
 func parseMatProperties(cMatPropsIn **C.struct_aiMaterialProperty, count uint) []*MaterialProperty {
+	if cMatPropsIn == nil {
+		return []*MaterialProperty{}
+	}
+
 	matProps := make([]*MaterialProperty, count)
 
-	cMatProps := unsafe.Slice((*C.struct_aiMaterialProperty)(unsafe.Pointer(cMatPropsIn)), int(count))
+	cMatProps := unsafe.Slice(cMatPropsIn, int(count))
 
 	for i := 0; i < int(count); i++ {
 
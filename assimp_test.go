@@ -1,6 +1,11 @@
 package assimp
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/flywave/go3d/mat4"
@@ -22,6 +27,100 @@ func TestBasicImport(t *testing.T) {
 	}
 }
 
+// TestImportFromMemory 测试从内存缓冲区导入的错误处理
+func TestImportFromMemory(t *testing.T) {
+	// 测试空缓冲区
+	_, release, err := ImportFromMemory(nil, "obj", PostProcessTriangulate)
+	if err == nil {
+		t.Error("Expected error for empty buffer, got nil")
+	}
+	release()
+
+	// 测试无法识别的数据
+	_, release, err = ImportFromMemory([]byte("definitely not a model"), "nonexistentformat", PostProcessTriangulate)
+	if err == nil {
+		t.Error("Expected error for unrecognized data, got nil")
+	}
+	release()
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+// TestImportReader 测试从io.Reader导入的错误处理
+func TestImportReader(t *testing.T) {
+	_, _, err := ImportReader(bytes.NewReader(nil), "obj", PostProcessTriangulate)
+	if err == nil {
+		t.Error("Expected error for empty reader, got nil")
+	}
+
+	_, _, err = ImportReader(failingReader{}, "obj", PostProcessTriangulate)
+	if err == nil || err.Error() != "read failed" {
+		t.Errorf("Expected reader error to be returned, got %v", err)
+	}
+}
+
+// TestImportFromMemoryRoundTrip 测试从内存和io.Reader导入的结果与ImportFile一致
+func TestImportFromMemoryRoundTrip(t *testing.T) {
+	for _, file := range []string{"hierarchy.gltf", "hierarchy.dae"} {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join("testdata", file)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", path, err)
+			}
+
+			want, release, err := ImportFile(path, PostProcessTriangulate)
+			if err != nil {
+				t.Fatalf("Unexpected import error: %v", err)
+			}
+			defer release()
+
+			hint := filepath.Ext(file)
+			fromMemory, releaseMemory, err := ImportFromMemory(data, hint, PostProcessTriangulate)
+			if err != nil {
+				t.Fatalf("Unexpected memory import error: %v", err)
+			}
+			defer releaseMemory()
+
+			fromReader, releaseReader, err := ImportReader(bytes.NewReader(data), hint, PostProcessTriangulate)
+			if err != nil {
+				t.Fatalf("Unexpected reader import error: %v", err)
+			}
+			defer releaseReader()
+
+			for name, got := range map[string]*Scene{"memory": fromMemory, "reader": fromReader} {
+				if len(got.Meshes) != len(want.Meshes) {
+					t.Fatalf("Expected %d meshes from %s, got %d", len(want.Meshes), name, len(got.Meshes))
+				}
+				for i, m := range got.Meshes {
+					if len(m.Vertices) != len(want.Meshes[i].Vertices) || len(m.Faces) != len(want.Meshes[i].Faces) {
+						t.Errorf("Expected mesh %d from %s to have %d vertices and %d faces, got %d and %d", i, name,
+							len(want.Meshes[i].Vertices), len(want.Meshes[i].Faces), len(m.Vertices), len(m.Faces))
+					}
+				}
+				if got, want := nodeNames(got.RootNode), nodeNames(want.RootNode); !slices.Equal(got, want) {
+					t.Errorf("Expected nodes %v from %s, got %v", want, name, got)
+				}
+			}
+		})
+	}
+}
+
+func nodeNames(n *Node) []string {
+	if n == nil {
+		return nil
+	}
+	names := []string{n.Name}
+	for _, c := range n.Children {
+		names = append(names, nodeNames(c)...)
+	}
+	return names
+}
+
 // TestPostProcessFlags 测试不同的后处理标志
 func TestPostProcessFlags(t *testing.T) {
 	// 这里我们主要测试标志的组合不会导致崩溃
@@ -0,0 +1,444 @@
+package assimp
+
+import (
+	"iter"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec3"
+)
+
+// Sphere is a bounding sphere.
+type Sphere struct {
+	Center vec3.T
+	Radius float32
+}
+
+// OBB is an oriented bounding box. Axes are orthonormal and right-handed,
+// ordered from the direction of largest to smallest spread of the points.
+type OBB struct {
+	Center      vec3.T
+	Axes        [3]vec3.T
+	HalfExtents vec3.T
+}
+
+// Corners returns the 8 corners of the box.
+func (b *OBB) Corners() [8]vec3.T {
+
+	var corners [8]vec3.T
+
+	for i := range corners {
+		c := b.Center
+		for a := 0; a < 3; a++ {
+			e := b.HalfExtents[a]
+			if i&(1<<a) == 0 {
+				e = -e
+			}
+			d := b.Axes[a].Scaled(e)
+			c.Add(&d)
+		}
+		corners[i] = c
+	}
+
+	return corners
+}
+
+// ComputeAABB returns the bounding box of the vertices of the mesh in mesh
+// space, the zero box if it has none. Unlike Mesh.AABB it doesn't need
+// PostProcessGenBoundingBoxes.
+func (m *Mesh) ComputeAABB() AABB {
+	box, _ := pointsAABB(slicePoints(m.Vertices))
+	return box
+}
+
+// BoundingSphere returns a bounding sphere of the vertices of the mesh in
+// mesh space, see Scene.BoundingSphere.
+func (m *Mesh) BoundingSphere() Sphere {
+	sphere, _ := ritterSphere(slicePoints(m.Vertices))
+	return sphere
+}
+
+// OrientedBounds returns an oriented bounding box of the vertices of the
+// mesh in mesh space, see Scene.OrientedBounds.
+func (m *Mesh) OrientedBounds() OBB {
+	obb, _ := pcaOBB(slicePoints(m.Vertices))
+	return obb
+}
+
+// boundsCache holds the bounds computed for a scene.
+type boundsCache struct {
+	mu sync.Mutex
+
+	// nodes holds the world space bounds of the meshes of a node and its
+	// descendants, for nodes that have any.
+	nodes map[*Node]AABB
+
+	sphere, obb     bool
+	sphereOK, obbOK bool
+	sphereVal       Sphere
+	obbVal          OBB
+}
+
+// boundsMu guards Scene.bounds.
+var boundsMu sync.Mutex
+
+func (s *Scene) boundsCache() *boundsCache {
+
+	boundsMu.Lock()
+	defer boundsMu.Unlock()
+
+	if s.bounds == nil {
+		s.bounds = &boundsCache{}
+	}
+
+	return s.bounds
+}
+
+// InvalidateBounds drops the bounds cached by Bounds, NodeBounds,
+// BoundingSphere and OrientedBounds. Call it after changing the nodes or
+// mesh vertices of the scene. ApplyPostProcessing and Close call it.
+func (s *Scene) InvalidateBounds() {
+
+	boundsMu.Lock()
+	defer boundsMu.Unlock()
+
+	s.bounds = nil
+}
+
+// Bounds returns the world space bounding box of all mesh instances of the
+// scene. ok is false if they have no vertices. The result is cached.
+func (s *Scene) Bounds() (box AABB, ok bool) {
+
+	if s.RootNode == nil {
+		return AABB{}, false
+	}
+
+	return s.NodeBounds(s.RootNode)
+}
+
+// NodeBounds returns the world space bounding box of the meshes of n and
+// its descendants. ok is false if they have no vertices or n isn't part of
+// the scene. The results for all nodes are computed on the first call and
+// cached.
+func (s *Scene) NodeBounds(n *Node) (box AABB, ok bool) {
+
+	c := s.boundsCache()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.nodes == nil {
+		c.nodes = map[*Node]AABB{}
+		if s.RootNode != nil {
+			s.collectNodeBounds(c.nodes, s.RootNode, &mat4.Ident)
+		}
+	}
+
+	box, ok = c.nodes[n]
+	return box, ok
+}
+
+// collectNodeBounds adds the bounds of n and its descendants to nodes.
+func (s *Scene) collectNodeBounds(nodes map[*Node]AABB, n *Node, parentWorld *mat4.T) (AABB, bool) {
+
+	var world mat4.T
+	world.AssignMul(parentWorld, n.localTransform())
+
+	var box AABB
+	var ok bool
+
+	grow := func(b AABB) {
+		if !ok {
+			box, ok = b, true
+			return
+		}
+		box.Min = vec3.Min(&box.Min, &b.Min)
+		box.Max = vec3.Max(&box.Max, &b.Max)
+	}
+
+	for _, mi := range n.MeshIndicies {
+		if mi < uint(len(s.Meshes)) && s.Meshes[mi] != nil {
+			if b, found := pointsAABB(transformedPoints(s.Meshes[mi].Vertices, &world)); found {
+				grow(b)
+			}
+		}
+	}
+
+	for _, child := range n.Children {
+		if child != nil {
+			if b, found := s.collectNodeBounds(nodes, child, &world); found {
+				grow(b)
+			}
+		}
+	}
+
+	if ok {
+		nodes[n] = box
+	}
+
+	return box, ok
+}
+
+// BoundingSphere returns a bounding sphere of all mesh instances of the
+// scene in world space, computed with Ritter's algorithm. It is within about
+// 5-20% of the minimal sphere. ok is false if there are no vertices. The
+// result is cached.
+func (s *Scene) BoundingSphere() (sphere Sphere, ok bool) {
+
+	c := s.boundsCache()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.sphere {
+		c.sphereVal, c.sphereOK = ritterSphere(s.worldPoints())
+		c.sphere = true
+	}
+
+	return c.sphereVal, c.sphereOK
+}
+
+// OrientedBounds returns an oriented bounding box of all mesh instances of
+// the scene in world space, with axes along the principal components of the
+// vertices. ok is false if there are no vertices. The result is cached.
+func (s *Scene) OrientedBounds() (obb OBB, ok bool) {
+
+	c := s.boundsCache()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.obb {
+		c.obbVal, c.obbOK = pcaOBB(s.worldPoints())
+		c.obb = true
+	}
+
+	return c.obbVal, c.obbOK
+}
+
+// worldPoints returns the vertices of all mesh instances in world space.
+func (s *Scene) worldPoints() iter.Seq[vec3.T] {
+	return func(yield func(vec3.T) bool) {
+		for inst := range s.MeshInstances() {
+			for p := range transformedPoints(inst.Mesh.Vertices, &inst.World) {
+				if !yield(p) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func slicePoints(points []vec3.T) iter.Seq[vec3.T] {
+	return func(yield func(vec3.T) bool) {
+		for _, p := range points {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+func transformedPoints(points []vec3.T, m *mat4.T) iter.Seq[vec3.T] {
+	return func(yield func(vec3.T) bool) {
+		for _, p := range points {
+			if !yield(transformPoint(m, p)) {
+				return
+			}
+		}
+	}
+}
+
+// pointsAABB returns the bounding box of points, ok is false if there are
+// none.
+func pointsAABB(points iter.Seq[vec3.T]) (box AABB, ok bool) {
+
+	for p := range points {
+
+		if !ok {
+			box, ok = AABB{Min: p, Max: p}, true
+			continue
+		}
+
+		box.Min = vec3.Min(&box.Min, &p)
+		box.Max = vec3.Max(&box.Max, &p)
+	}
+
+	return box, ok
+}
+
+// ritterSphere computes a bounding sphere with Ritter's algorithm: a sphere
+// through two distant points is grown to include every point outside it.
+func ritterSphere(points iter.Seq[vec3.T]) (Sphere, bool) {
+
+	var first vec3.T
+	var found bool
+	for p := range points {
+		first, found = p, true
+		break
+	}
+
+	if !found {
+		return Sphere{}, false
+	}
+
+	farthest := func(from vec3.T) vec3.T {
+		best, bestDist := from, float32(-1)
+		for p := range points {
+			if d := vec3.SquareDistance(&p, &from); d > bestDist {
+				best, bestDist = p, d
+			}
+		}
+		return best
+	}
+
+	y := farthest(first)
+	z := farthest(y)
+
+	center := vec3.Interpolate(&y, &z, 0.5)
+	radius := vec3.Distance(&y, &z) / 2
+
+	for p := range points {
+
+		d := vec3.Distance(&p, &center)
+		if d <= radius {
+			continue
+		}
+
+		newRadius := (radius + d) / 2
+		dir := vec3.Sub(&p, &center)
+		dir.Scale((newRadius - radius) / d)
+		center.Add(&dir)
+		radius = newRadius
+	}
+
+	return Sphere{Center: center, Radius: radius}, true
+}
+
+// pcaOBB computes an oriented bounding box with axes along the eigenvectors
+// of the covariance matrix of points.
+func pcaOBB(points iter.Seq[vec3.T]) (OBB, bool) {
+
+	var mean [3]float64
+	n := 0
+	for p := range points {
+		for i := range mean {
+			mean[i] += float64(p[i])
+		}
+		n++
+	}
+
+	if n == 0 {
+		return OBB{Axes: [3]vec3.T{vec3.UnitX, vec3.UnitY, vec3.UnitZ}}, false
+	}
+
+	for i := range mean {
+		mean[i] /= float64(n)
+	}
+
+	var cov [3][3]float64
+	for p := range points {
+		d := [3]float64{float64(p[0]) - mean[0], float64(p[1]) - mean[1], float64(p[2]) - mean[2]}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				cov[i][j] += d[i] * d[j]
+			}
+		}
+	}
+
+	axes := eigenAxes(cov)
+
+	var lo, hi [3]float64
+	for i := range lo {
+		lo[i], hi[i] = math.Inf(1), math.Inf(-1)
+	}
+
+	for p := range points {
+		for i, a := range axes {
+			d := float64(p[0])*a[0] + float64(p[1])*a[1] + float64(p[2])*a[2]
+			lo[i] = math.Min(lo[i], d)
+			hi[i] = math.Max(hi[i], d)
+		}
+	}
+
+	var obb OBB
+	for i, a := range axes {
+
+		obb.Axes[i] = vec3.T{float32(a[0]), float32(a[1]), float32(a[2])}
+		obb.HalfExtents[i] = float32((hi[i] - lo[i]) / 2)
+
+		c := obb.Axes[i].Scaled(float32((hi[i] + lo[i]) / 2))
+		obb.Center.Add(&c)
+	}
+
+	return obb, true
+}
+
+// eigenAxes returns the eigenvectors of the symmetric matrix a, computed
+// with the cyclic Jacobi method, ordered by descending eigenvalue and made
+// right-handed.
+func eigenAxes(a [3][3]float64) [3][3]float64 {
+
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	for sweep := 0; sweep < 50; sweep++ {
+
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		if off < 1e-24 {
+			break
+		}
+
+		for _, pq := range [3][2]int{{0, 1}, {0, 2}, {1, 2}} {
+
+			p, q := pq[0], pq[1]
+			if math.Abs(a[p][q]) < 1e-30 {
+				continue
+			}
+
+			theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+			t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+			if theta < 0 {
+				t = -t
+			}
+			c := 1 / math.Sqrt(t*t+1)
+			s := t * c
+
+			// a = J^T a J, v = v J with the rotation J in the p,q plane.
+			for k := 0; k < 3; k++ {
+				akp, akq := a[k][p], a[k][q]
+				a[k][p] = c*akp - s*akq
+				a[k][q] = s*akp + c*akq
+			}
+			for k := 0; k < 3; k++ {
+				apk, aqk := a[p][k], a[q][k]
+				a[p][k] = c*apk - s*aqk
+				a[q][k] = s*apk + c*aqk
+			}
+			for k := 0; k < 3; k++ {
+				vkp, vkq := v[k][p], v[k][q]
+				v[k][p] = c*vkp - s*vkq
+				v[k][q] = s*vkp + c*vkq
+			}
+		}
+	}
+
+	order := []int{0, 1, 2}
+	sort.SliceStable(order, func(i, j int) bool {
+		return a[order[i]][order[i]] > a[order[j]][order[j]]
+	})
+
+	var axes [3][3]float64
+	for i, col := range order {
+		axes[i] = [3]float64{v[0][col], v[1][col], v[2][col]}
+	}
+
+	axes[2] = [3]float64{
+		axes[0][1]*axes[1][2] - axes[0][2]*axes[1][1],
+		axes[0][2]*axes[1][0] - axes[0][0]*axes[1][2],
+		axes[0][0]*axes[1][1] - axes[0][1]*axes[1][0],
+	}
+
+	return axes
+}
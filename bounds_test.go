@@ -0,0 +1,162 @@
+package assimp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/quaternion"
+	"github.com/flywave/go3d/vec3"
+)
+
+// boxVertices 返回以原点为中心, 半边长为hx,hy,hz的长方体的8个顶点
+func boxVertices(hx, hy, hz float32) []vec3.T {
+	var v []vec3.T
+	for _, x := range []float32{-hx, hx} {
+		for _, y := range []float32{-hy, hy} {
+			for _, z := range []float32{-hz, hz} {
+				v = append(v, vec3.T{x, y, z})
+			}
+		}
+	}
+	return v
+}
+
+// TestMeshComputeAABB 测试网格局部空间的包围盒
+func TestMeshComputeAABB(t *testing.T) {
+	m := &Mesh{Vertices: []vec3.T{{1, -2, 3}, {-1, 4, 0}, {0, 0, 5}}}
+
+	box := m.ComputeAABB()
+	if box.Min != (vec3.T{-1, -2, 0}) || box.Max != (vec3.T{1, 4, 5}) {
+		t.Errorf("Unexpected AABB %v", box)
+	}
+
+	if (&Mesh{}).ComputeAABB() != (AABB{}) {
+		t.Error("Expected the zero box for a mesh without vertices")
+	}
+}
+
+// TestSceneWorldBounds 测试场景和节点的世界空间包围盒
+func TestSceneWorldBounds(t *testing.T) {
+	scene := buildFlattenScene()
+
+	box, ok := scene.Bounds()
+	if !ok || box.Min != (vec3.T{0, 0, 0}) || box.Max != (vec3.T{3, 1, 0}) {
+		t.Errorf("Unexpected scene bounds %v, %v", box, ok)
+	}
+
+	box, ok = scene.NodeBounds(scene.FindNode("mirrored"))
+	if !ok || box.Min != (vec3.T{0, 0, 0}) || box.Max != (vec3.T{1, 1, 0}) {
+		t.Errorf("Unexpected bounds of the mirrored node %v, %v", box, ok)
+	}
+
+	if _, ok := scene.NodeBounds(&Node{Name: "stranger"}); ok {
+		t.Error("Expected no bounds for a node outside the scene")
+	}
+
+	if _, ok := (&Scene{}).Bounds(); ok {
+		t.Error("Expected no bounds for an empty scene")
+	}
+}
+
+// TestSceneBoundsCache 测试包围盒缓存及其失效
+func TestSceneBoundsCache(t *testing.T) {
+	scene := buildFlattenScene()
+
+	before, _ := scene.Bounds()
+
+	// 修改顶点后, 缓存的结果保持不变直到调用InvalidateBounds
+	scene.Meshes[0].Vertices[0] = vec3.T{10, 0, 0}
+	if cached, _ := scene.Bounds(); cached != before {
+		t.Error("Expected cached bounds before invalidation")
+	}
+
+	scene.InvalidateBounds()
+	if after, _ := scene.Bounds(); after.Max[0] != 21 {
+		t.Errorf("Expected bounds to be recomputed, got %v", after)
+	}
+
+	scene.Close()
+	if scene.bounds != nil {
+		t.Error("Expected Close to drop the cached bounds")
+	}
+}
+
+// TestBoundingSphere 测试包围球包含所有顶点且接近最小包围球
+func TestBoundingSphere(t *testing.T) {
+	translate := mat4.Ident
+	translate.SetTranslation(&vec3.T{5, 0, 0})
+
+	root := &Node{Name: "root", Transformation: &translate, MeshIndicies: []uint{0}}
+	scene := &Scene{RootNode: root, Meshes: []*Mesh{{Vertices: boxVertices(1, 2, 3)}}}
+
+	sphere, ok := scene.BoundingSphere()
+	if !ok {
+		t.Fatal("Expected a bounding sphere")
+	}
+
+	for p := range scene.worldPoints() {
+		if d := vec3.Distance(&p, &sphere.Center); d > sphere.Radius*1.0001 {
+			t.Errorf("Expected %v inside the sphere %v, distance %v", p, sphere, d)
+		}
+	}
+
+	// 长方体的最小包围球半径为半对角线长度
+	minimal := float32(math.Sqrt(1 + 4 + 9))
+	if sphere.Radius > minimal*1.2 {
+		t.Errorf("Expected radius close to %v, got %v", minimal, sphere.Radius)
+	}
+	if !approxVec3(sphere.Center, vec3.T{5, 0, 0}) {
+		t.Errorf("Expected center at (5,0,0), got %v", sphere.Center)
+	}
+
+	if _, ok := (&Scene{}).BoundingSphere(); ok {
+		t.Error("Expected no bounding sphere for an empty scene")
+	}
+}
+
+// TestOrientedBounds 测试旋转后的长方体能还原出其方向和尺寸
+func TestOrientedBounds(t *testing.T) {
+	q := quaternion.FromEulerAngles(0.3, 0.7, -0.4)
+	rot := mat4.Ident
+	rot.AssignQuaternion(&q)
+	rot.SetTranslation(&vec3.T{1, 2, 3})
+
+	root := &Node{Name: "root", Transformation: &rot, MeshIndicies: []uint{0}}
+	scene := &Scene{RootNode: root, Meshes: []*Mesh{{Vertices: boxVertices(4, 2, 1)}}}
+
+	obb, ok := scene.OrientedBounds()
+	if !ok {
+		t.Fatal("Expected an oriented bounding box")
+	}
+
+	if !approxVec3(obb.Center, vec3.T{1, 2, 3}) {
+		t.Errorf("Expected center (1,2,3), got %v", obb.Center)
+	}
+	if !approxVec3(obb.HalfExtents, vec3.T{4, 2, 1}) {
+		t.Errorf("Expected half extents (4,2,1), got %v", obb.HalfExtents)
+	}
+
+	// 主轴应与旋转后的X轴平行
+	x := transformDir(&rot, vec3.UnitX)
+	if d := vec3.Dot(&x, &obb.Axes[0]); math.Abs(float64(d)) < 0.9999 {
+		t.Errorf("Expected first axis along %v, got %v", x, obb.Axes[0])
+	}
+
+	cross := vec3.Cross(&obb.Axes[0], &obb.Axes[1])
+	if !approxVec3(cross, obb.Axes[2]) {
+		t.Error("Expected right-handed axes")
+	}
+
+	for _, c := range obb.Corners() {
+		found := false
+		for p := range scene.worldPoints() {
+			if approxVec3(c, p) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected corner %v to match a box vertex", c)
+		}
+	}
+}
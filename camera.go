@@ -10,37 +10,57 @@ import "C"
 import "github.com/flywave/go3d/vec3"
 
 type Camera struct {
-	c *C.struct_aiCamera
+	name          string
+	position      vec3.T
+	up            vec3.T
+	lookAt        vec3.T
+	horizontalFov float32
+	clipPlaneNear float32
+	clipPlaneFar  float32
+	aspect        float32
 }
 
 func (cam *Camera) Name() string {
-	return C.GoStringN(&cam.c.mName.data[0], C.int(cam.c.mName.length))
+	return cam.name
 }
 
 func (cam *Camera) Position() vec3.T {
-	return vec3.T{float32(cam.c.mPosition.x), float32(cam.c.mPosition.y), float32(cam.c.mPosition.z)}
+	return cam.position
 }
 
 func (cam *Camera) Up() vec3.T {
-	return vec3.T{float32(cam.c.mUp.x), float32(cam.c.mUp.y), float32(cam.c.mUp.z)}
+	return cam.up
 }
 
 func (cam *Camera) LookAt() vec3.T {
-	return vec3.T{float32(cam.c.mLookAt.x), float32(cam.c.mLookAt.y), float32(cam.c.mLookAt.z)}
+	return cam.lookAt
 }
 
 func (cam *Camera) HorizontalFov() float32 {
-	return float32(cam.c.mHorizontalFOV)
+	return cam.horizontalFov
 }
 
 func (cam *Camera) ClipPlaneNear() float32 {
-	return float32(cam.c.mClipPlaneNear)
+	return cam.clipPlaneNear
 }
 
 func (cam *Camera) ClipPlaneFar() float32 {
-	return float32(cam.c.mClipPlaneFar)
+	return cam.clipPlaneFar
 }
 
 func (cam *Camera) Aspect() float32 {
-	return float32(cam.c.mAspect)
+	return cam.aspect
+}
+
+func parseCamera(cc *C.struct_aiCamera) *Camera {
+	return &Camera{
+		name:          parseAiString(cc.mName),
+		position:      parseVec3(&cc.mPosition),
+		up:            parseVec3(&cc.mUp),
+		lookAt:        parseVec3(&cc.mLookAt),
+		horizontalFov: float32(cc.mHorizontalFOV),
+		clipPlaneNear: float32(cc.mClipPlaneNear),
+		clipPlaneFar:  float32(cc.mClipPlaneFar),
+		aspect:        float32(cc.mAspect),
+	}
 }
@@ -1,5 +1,19 @@
 package assimp
 
+/*
+#cgo linux CFLAGS: -I ./lib/linux
+#cgo darwin,amd64 CFLAGS: -I ./lib/darwin
+#cgo darwin,arm64 CFLAGS: -I ./lib/darwin_arm
+
+#include <stdlib.h>
+#include <assimp/scene.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
 type aiReturn int32
 
 const (
@@ -19,15 +33,49 @@ const (
 	SceneFlagAllowShared       SceneFlag = 1 << 5
 )
 
+// PrimitiveType is a set of face kinds, see Mesh.PrimitiveTypes.
 type PrimitiveType int32
 
 const (
-	PrimitiveTypePoint    = 1 << 0
-	PrimitiveTypeLine     = 1 << 1
-	PrimitiveTypeTriangle = 1 << 2
-	PrimitiveTypePolygon  = 1 << 3
+	PrimitiveTypePoint    PrimitiveType = 1 << 0
+	PrimitiveTypeLine     PrimitiveType = 1 << 1
+	PrimitiveTypeTriangle PrimitiveType = 1 << 2
+	PrimitiveTypePolygon  PrimitiveType = 1 << 3
+	// PrimitiveTypeNGONEncodingFlag marks meshes whose polygons were
+	// triangulated with consecutive triangles sharing their first vertex.
+	PrimitiveTypeNGONEncodingFlag PrimitiveType = 1 << 4
 )
 
+func (pt PrimitiveType) String() string {
+
+	if pt == 0 {
+		return "None"
+	}
+
+	var names []string
+	for _, f := range []struct {
+		flag PrimitiveType
+		name string
+	}{
+		{PrimitiveTypePoint, "Point"},
+		{PrimitiveTypeLine, "Line"},
+		{PrimitiveTypeTriangle, "Triangle"},
+		{PrimitiveTypePolygon, "Polygon"},
+		{PrimitiveTypeNGONEncodingFlag, "NGONEncoding"},
+	} {
+		if pt&f.flag != 0 {
+			names = append(names, f.name)
+			pt &^= f.flag
+		}
+	}
+
+	if pt != 0 {
+		names = append(names, fmt.Sprintf("0x%x", int32(pt)))
+	}
+
+	return strings.Join(names, "|")
+}
+
 type MorphMethod int32
 
 const (
@@ -177,12 +225,67 @@ func (mpti MatPropertyTypeInfo) String() string {
 type MetadataType int32
 
 const (
-	MetadataTypeBool    MetadataType = 0
-	MetadataTypeInt32   MetadataType = 1
-	MetadataTypeUint64  MetadataType = 2
-	MetadataTypeFloat32 MetadataType = 3
-	MetadataTypeFloat64 MetadataType = 4
-	MetadataTypeString  MetadataType = 5
-	MetadataTypeVec3    MetadataType = 6
-	MetadataTypeMAX     MetadataType = 7
+	MetadataTypeBool    MetadataType = C.AI_BOOL
+	MetadataTypeInt32   MetadataType = C.AI_INT32
+	MetadataTypeUint64  MetadataType = C.AI_UINT64
+	MetadataTypeFloat32 MetadataType = C.AI_FLOAT
+	MetadataTypeFloat64 MetadataType = C.AI_DOUBLE
+	MetadataTypeString  MetadataType = C.AI_AISTRING
+	MetadataTypeVec3    MetadataType = C.AI_AIVECTOR3D
+	// MetadataTypeMetadata is a nested map[string]Metadata, e.g. an IFC
+	// property set.
+	MetadataTypeMetadata MetadataType = C.AI_AIMETADATA
+	MetadataTypeMAX      MetadataType = C.AI_META_MAX
 )
+
+func (mt MetadataType) String() string {
+
+	switch mt {
+	case MetadataTypeBool:
+		return "Bool"
+	case MetadataTypeInt32:
+		return "Int32"
+	case MetadataTypeUint64:
+		return "Uint64"
+	case MetadataTypeFloat32:
+		return "Float32"
+	case MetadataTypeFloat64:
+		return "Float64"
+	case MetadataTypeString:
+		return "String"
+	case MetadataTypeVec3:
+		return "Vec3"
+	case MetadataTypeMetadata:
+		return "Metadata"
+	default:
+		return "Unknown"
+	}
+}
+
+// Component selects parts of the scene that PostProcessRemoveComponent strips
+// during import. See ImportOptions.RemoveComponents.
+type Component uint32
+
+const (
+	ComponentNormals               Component = 0x2
+	ComponentTangentsAndBitangents Component = 0x4
+	ComponentColors                Component = 0x8
+	ComponentTexCoords             Component = 0x10
+	ComponentBoneWeights           Component = 0x20
+	ComponentAnimations            Component = 0x40
+	ComponentTextures              Component = 0x80
+	ComponentLights                Component = 0x100
+	ComponentCameras               Component = 0x200
+	ComponentMeshes                Component = 0x400
+	ComponentMaterials             Component = 0x800
+)
+
+// ComponentColorsN selects only the n'th vertex color set.
+func ComponentColorsN(n uint) Component {
+	return Component(1) << (n + 20)
+}
+
+// ComponentTexCoordsN selects only the n'th texture coordinate set.
+func ComponentTexCoordsN(n uint) Component {
+	return Component(1) << (n + 25)
+}
@@ -0,0 +1,79 @@
+package assimp
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// Sentinel errors for the kind of import failure. ImportError wraps one of
+// them, so they can be checked with errors.Is.
+var (
+	ErrFileNotFound      = errors.New("assimp: file not found")
+	ErrUnsupportedFormat = errors.New("assimp: unsupported format")
+	ErrParseFailed       = errors.New("assimp: parse failed")
+	ErrValidationFailed  = errors.New("assimp: validation failed")
+	// ErrImportCrashed is wrapped when the process running an isolated
+	// import died before returning a scene.
+	ErrImportCrashed = errors.New("assimp: import process crashed")
+)
+
+// ErrSceneClosed is returned by Scene methods that need the imported data
+// after the scene has been closed.
+var ErrSceneClosed = errors.New("assimp: scene closed")
+
+// ImportError is returned when Assimp fails to import a scene.
+type ImportError struct {
+	// Path is the imported file, empty for imports from memory.
+	Path string
+	// Hint is the format hint of an import from memory, or the extension of
+	// Path otherwise.
+	Hint string
+	// Message is the error text reported by Assimp.
+	Message string
+	// Err is one of ErrFileNotFound, ErrUnsupportedFormat, ErrParseFailed
+	// and ErrValidationFailed, or ErrImportCrashed and the context error for
+	// ImportIsolated.
+	Err error
+}
+
+func (e *ImportError) Error() string {
+	if e.Path == "" {
+		return "asig error: " + e.Message
+	}
+
+	return "asig error: " + e.Path + ": " + e.Message
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// newImportError builds an ImportError for path from the message Assimp
+// reported, classifying it by the wording Assimp's importer uses.
+func newImportError(path, hint, msg string) *ImportError {
+
+	if hint == "" {
+		hint = filepath.Ext(path)
+	}
+
+	return &ImportError{
+		Path:    path,
+		Hint:    strings.TrimPrefix(hint, "."),
+		Message: msg,
+		Err:     classifyImportError(msg),
+	}
+}
+
+func classifyImportError(msg string) error {
+	switch {
+	case strings.HasPrefix(msg, "Unable to open file"):
+		return ErrFileNotFound
+	case strings.HasPrefix(msg, "No suitable reader found"):
+		return ErrUnsupportedFormat
+	case strings.Contains(msg, "Validation failed"):
+		return ErrValidationFailed
+	default:
+		return ErrParseFailed
+	}
+}
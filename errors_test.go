@@ -0,0 +1,84 @@
+package assimp
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestClassifyImportError 测试Assimp错误信息的分类
+func TestClassifyImportError(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected error
+	}{
+		{`Unable to open file "model.obj".`, ErrFileNotFound},
+		{`No suitable reader found for the file format of file "model.xyz".`, ErrUnsupportedFormat},
+		{"Validation failed: aiMesh::mFaces[0].mIndices[0] is out of range", ErrValidationFailed},
+		{"OBJ: Invalid face indice", ErrParseFailed},
+		{"", ErrParseFailed},
+	}
+
+	for _, test := range tests {
+		if err := classifyImportError(test.msg); err != test.expected {
+			t.Errorf("classifyImportError(%q) = %v, expected %v", test.msg, err, test.expected)
+		}
+	}
+}
+
+// TestImportErrorIs 测试ImportError与errors.Is/errors.As的配合
+func TestImportErrorIs(t *testing.T) {
+	var err error = newImportError("models/cube.obj", "", `Unable to open file "models/cube.obj".`)
+
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Error("Expected error to match ErrFileNotFound")
+	}
+
+	if errors.Is(err, ErrParseFailed) {
+		t.Error("Expected error not to match ErrParseFailed")
+	}
+
+	var ie *ImportError
+	if !errors.As(err, &ie) {
+		t.Fatal("Expected error to be an *ImportError")
+	}
+
+	if ie.Path != "models/cube.obj" || ie.Hint != "obj" {
+		t.Errorf("Expected path and hint to be recorded, got %q and %q", ie.Path, ie.Hint)
+	}
+
+	if err.Error() != `asig error: models/cube.obj: Unable to open file "models/cube.obj".` {
+		t.Errorf("Unexpected error text %q", err.Error())
+	}
+}
+
+// TestImportFileError 测试导入不存在的文件返回结构化错误
+func TestImportFileError(t *testing.T) {
+	_, _, err := ImportFile("nonexistent.obj", PostProcessTriangulate)
+
+	var ie *ImportError
+	if !errors.As(err, &ie) {
+		t.Fatalf("Expected *ImportError, got %T", err)
+	}
+
+	if ie.Path != "nonexistent.obj" {
+		t.Errorf("Expected path 'nonexistent.obj', got '%s'", ie.Path)
+	}
+
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got %v", ie.Err)
+	}
+}
+
+// TestImportFromMemoryErrorHint 测试内存导入错误携带格式提示
+func TestImportFromMemoryErrorHint(t *testing.T) {
+	_, _, err := ImportFromMemory(nil, ".glb", 0)
+
+	var ie *ImportError
+	if !errors.As(err, &ie) {
+		t.Fatalf("Expected *ImportError, got %T", err)
+	}
+
+	if ie.Hint != "glb" || ie.Path != "" {
+		t.Errorf("Expected hint 'glb' and empty path, got %q and %q", ie.Hint, ie.Path)
+	}
+}
@@ -0,0 +1,269 @@
+package assimp
+
+/*
+#cgo linux CFLAGS: -I ./lib/linux
+#cgo darwin,amd64 CFLAGS: -I ./lib/darwin
+#cgo darwin,arm64 CFLAGS: -I ./lib/darwin_arm
+
+#include <stdint.h>
+#include <stdlib.h>
+#include <assimp/cfileio.h>
+#include <assimp/cimport.h>
+
+// goFileIO and goFile extend the Assimp callback structs with a cgo.Handle
+// that points back at the Go side. The Assimp struct must stay the first field.
+typedef struct {
+	struct aiFileIO io;
+	uintptr_t handle;
+} goFileIO;
+
+typedef struct {
+	struct aiFile file;
+	uintptr_t handle;
+} goFile;
+
+struct aiFile* goFSOpen(struct aiFileIO* io, char* name, char* mode);
+void goFSClose(struct aiFileIO* io, struct aiFile* file);
+size_t goFSRead(struct aiFile* file, char* buffer, size_t size, size_t count);
+size_t goFSWrite(struct aiFile* file, char* buffer, size_t size, size_t count);
+size_t goFSTell(struct aiFile* file);
+size_t goFSSize(struct aiFile* file);
+enum aiReturn goFSSeek(struct aiFile* file, size_t offset, enum aiOrigin origin);
+void goFSFlush(struct aiFile* file);
+*/
+import "C"
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+)
+
+// fsIO is the Go side of an aiFileIO. It resolves every path Assimp asks for
+// inside fsys, so relative references of multi-file formats stay in the FS.
+type fsIO struct {
+	fsys fs.FS
+}
+
+// fsFile is the Go side of an aiFile.
+type fsFile struct {
+	r    io.ReadSeeker
+	c    io.Closer
+	size int64
+}
+
+// newFileIO allocates an aiFileIO in C memory that forwards to fsys. The
+// returned func frees it and must be called once the import is released.
+func newFileIO(fsys fs.FS) (*C.struct_aiFileIO, func()) {
+
+	h := cgo.NewHandle(&fsIO{fsys: fsys})
+
+	gio := (*C.goFileIO)(C.calloc(1, C.sizeof_goFileIO))
+	gio.io.OpenProc = C.aiFileOpenProc(C.goFSOpen)
+	gio.io.CloseProc = C.aiFileCloseProc(C.goFSClose)
+	gio.handle = C.uintptr_t(h)
+
+	return &gio.io, func() {
+		h.Delete()
+		C.free(unsafe.Pointer(gio))
+	}
+}
+
+// fsPath converts a path handed out by Assimp into an io/fs path. Assimp
+// joins relative references with the OS separator and may produce "./" or
+// duplicated separators, so the path is cleaned before use.
+func fsPath(name string) (string, bool) {
+
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Clean("/" + name)
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		name = "."
+	}
+
+	return name, fs.ValidPath(name)
+}
+
+func openFSFile(fsys fs.FS, name string) (*fsFile, error) {
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if st.IsDir() {
+		f.Close()
+		return nil, errors.New("is a directory")
+	}
+
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return &fsFile{r: rs, c: f, size: st.Size()}, nil
+	}
+
+	// Not every fs.File can seek (zip entries for one), but Assimp
+	// importers expect to, so buffer those files in memory.
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fsFile{r: bytes.NewReader(data), c: io.NopCloser(nil), size: int64(len(data))}, nil
+}
+
+func goFileFromC(cf *C.struct_aiFile) *fsFile {
+	return cgo.Handle((*C.goFile)(unsafe.Pointer(cf)).handle).Value().(*fsFile)
+}
+
+//export goFSOpen
+func goFSOpen(cio *C.struct_aiFileIO, cname *C.char, cmode *C.char) *C.struct_aiFile {
+
+	// The FS is read-only.
+	if strings.ContainsAny(C.GoString(cmode), "wa+") {
+		return nil
+	}
+
+	name, ok := fsPath(C.GoString(cname))
+	if !ok {
+		return nil
+	}
+
+	fio := cgo.Handle((*C.goFileIO)(unsafe.Pointer(cio)).handle).Value().(*fsIO)
+
+	f, err := openFSFile(fio.fsys, name)
+	if err != nil {
+		return nil
+	}
+
+	gf := (*C.goFile)(C.calloc(1, C.sizeof_goFile))
+	gf.file.ReadProc = C.aiFileReadProc(C.goFSRead)
+	gf.file.WriteProc = C.aiFileWriteProc(C.goFSWrite)
+	gf.file.TellProc = C.aiFileTellProc(C.goFSTell)
+	gf.file.FileSizeProc = C.aiFileTellProc(C.goFSSize)
+	gf.file.SeekProc = C.aiFileSeek(C.goFSSeek)
+	gf.file.FlushProc = C.aiFileFlushProc(C.goFSFlush)
+	gf.handle = C.uintptr_t(cgo.NewHandle(f))
+
+	return &gf.file
+}
+
+//export goFSClose
+func goFSClose(cio *C.struct_aiFileIO, cf *C.struct_aiFile) {
+
+	if cf == nil {
+		return
+	}
+
+	gf := (*C.goFile)(unsafe.Pointer(cf))
+	h := cgo.Handle(gf.handle)
+	h.Value().(*fsFile).c.Close()
+	h.Delete()
+
+	C.free(unsafe.Pointer(gf))
+}
+
+//export goFSRead
+func goFSRead(cf *C.struct_aiFile, buffer *C.char, size C.size_t, count C.size_t) C.size_t {
+
+	if size == 0 || count == 0 {
+		return 0
+	}
+
+	f := goFileFromC(cf)
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(size*count))
+
+	n, err := io.ReadFull(f.r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return 0
+	}
+
+	return C.size_t(n) / size
+}
+
+//export goFSWrite
+func goFSWrite(cf *C.struct_aiFile, buffer *C.char, size C.size_t, count C.size_t) C.size_t {
+	return 0
+}
+
+//export goFSTell
+func goFSTell(cf *C.struct_aiFile) C.size_t {
+
+	pos, err := goFileFromC(cf).r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0
+	}
+
+	return C.size_t(pos)
+}
+
+//export goFSSize
+func goFSSize(cf *C.struct_aiFile) C.size_t {
+	return C.size_t(goFileFromC(cf).size)
+}
+
+//export goFSSeek
+func goFSSeek(cf *C.struct_aiFile, offset C.size_t, origin C.enum_aiOrigin) C.enum_aiReturn {
+
+	whence := io.SeekStart
+	switch origin {
+	case C.aiOrigin_CUR:
+		whence = io.SeekCurrent
+	case C.aiOrigin_END:
+		whence = io.SeekEnd
+	}
+
+	f := goFileFromC(cf)
+
+	// Offsets relative to the current position or the end arrive as
+	// wrapped size_t values, so reinterpret them as signed.
+	pos, err := f.r.Seek(int64(offset), whence)
+	if err != nil || pos > f.size {
+		return C.aiReturn_FAILURE
+	}
+
+	return C.aiReturn_SUCCESS
+}
+
+//export goFSFlush
+func goFSFlush(cf *C.struct_aiFile) {
+}
+
+// ImportFS imports the file name from fsys. Every file Assimp opens while
+// importing, including sibling files referenced by the model (MTL libraries,
+// external glTF buffers, textures), is resolved relative to the root of fsys.
+// This makes embed.FS, zip.Reader, fstest.MapFS and similar usable as the
+// source of multi-file formats.
+func ImportFS(fsys fs.FS, name string, postProcessFlags PostProcess) (s *Scene, release func(), err error) {
+
+	if !fs.ValidPath(name) {
+		return nil, func() {}, &ImportError{Path: name, Message: "invalid path", Err: ErrFileNotFound}
+	}
+
+	fio, freeIO := newFileIO(fsys)
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	cs, session := importLocked(nil, func() *C.struct_aiScene {
+		return C.aiImportFileEx(cname, C.uint(postProcessFlags), fio)
+	})
+	if cs == nil {
+		freeIO()
+		return nil, func() {}, newImportError(name, "", session.errString)
+	}
+
+	// The importer keeps the file IO until the scene is released.
+	s = newImportedScene(cs, session, nil, postProcessFlags)
+	s.handle.freeIO = freeIO
+	return s, func() { s.Close() }, nil
+}
@@ -0,0 +1,109 @@
+package assimp
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+// TestFSPath 测试Assimp路径到io/fs路径的转换
+func TestFSPath(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+		valid    bool
+	}{
+		{"model.obj", "model.obj", true},
+		{"./model.mtl", "model.mtl", true},
+		{"models//cube.obj", "models/cube.obj", true},
+		{"models\\textures\\wood.png", "models/textures/wood.png", true},
+		{"/models/cube.mtl", "models/cube.mtl", true},
+		{"models/../cube.bin", "cube.bin", true},
+		{"", ".", true},
+	}
+
+	for _, test := range tests {
+		result, valid := fsPath(test.in)
+		if result != test.expected || valid != test.valid {
+			t.Errorf("fsPath(%q) = %q, %v; expected %q, %v", test.in, result, valid, test.expected, test.valid)
+		}
+	}
+}
+
+// TestOpenFSFile 测试从fs.FS打开文件
+func TestOpenFSFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"models/cube.obj": {Data: []byte("v 0 0 0\n")},
+	}
+
+	f, err := openFSFile(fsys, "models/cube.obj")
+	if err != nil {
+		t.Fatalf("Expected file to open, got %v", err)
+	}
+	defer f.c.Close()
+
+	if f.size != 8 {
+		t.Errorf("Expected size 8, got %d", f.size)
+	}
+
+	if _, err := openFSFile(fsys, "models"); err == nil {
+		t.Error("Expected error when opening a directory")
+	}
+
+	if _, err := openFSFile(fsys, "missing.mtl"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// TestImportFS 测试从fs.FS导入的错误处理
+func TestImportFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	_, _, err := ImportFS(fsys, "missing.obj", PostProcessTriangulate)
+	if err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+
+	_, _, err = ImportFS(fsys, "../outside.obj", PostProcessTriangulate)
+	if err == nil {
+		t.Error("Expected error for invalid path, got nil")
+	}
+}
+
+// TestImportFSEmbedTextures 测试导入后的后处理仍可通过fs.FS读取纹理
+func TestImportFSEmbedTextures(t *testing.T) {
+	var tex bytes.Buffer
+	if err := png.Encode(&tex, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"models/quad.obj":          {Data: []byte("mtllib quad.mtl\nv 0 0 0\nv 1 0 0\nv 1 1 0\nvt 0 0\nvt 1 0\nvt 1 1\nusemtl wood\nf 1/1 2/2 3/3\n")},
+		"models/quad.mtl":          {Data: []byte("newmtl wood\nmap_Kd textures/wood.png\n")},
+		"models/textures/wood.png": {Data: tex.Bytes()},
+	}
+
+	scene, release, err := ImportFS(fsys, "models/quad.obj", PostProcessTriangulate)
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer release()
+
+	if len(scene.Textures) != 0 {
+		t.Fatalf("Expected no embedded textures before post-processing, got %d", len(scene.Textures))
+	}
+
+	// 导入返回后文件IO必须仍然有效
+	runtime.GC()
+
+	if err := scene.ApplyPostProcessing(PostProcessEmbedTextures); err != nil {
+		t.Fatalf("Unexpected post-processing error: %v", err)
+	}
+
+	if len(scene.Textures) != 1 || !bytes.Equal(scene.Textures[0].Data, tex.Bytes()) {
+		t.Errorf("Expected the texture to be embedded from the FS, got %d textures", len(scene.Textures))
+	}
+}
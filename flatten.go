@@ -0,0 +1,429 @@
+package assimp
+
+import (
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec3"
+	"github.com/flywave/go3d/vec4"
+)
+
+// FlattenOptions configures Scene.Flatten.
+type FlattenOptions struct {
+	// MergeByMaterial merges all baked meshes that share a material into one
+	// mesh. Attributes are kept only if every merged mesh has them; bones and
+	// anim meshes are dropped.
+	MergeByMaterial bool
+	// Keep, if set, is called for every node. Nodes it returns true for are
+	// not flattened: they are copied with their subtree below the new root,
+	// with their world transform as Transformation, and their meshes are
+	// copied unbaked.
+	Keep func(n *Node) bool
+}
+
+// Flatten returns a new Go-owned scene in which every mesh instance has the
+// world transform of its node baked into its vertices, normals and tangents,
+// like PostProcessPreTransformVertices does during import. The baked meshes
+// are referenced by the new root node, which has the identity transform.
+// Normals use the inverse-transpose of the transform and mirroring
+// transforms flip the winding of the faces, so front faces stay front faces.
+//
+// Materials and textures are shared with s. Animations, lights, cameras and
+// metadata are copied, so normalizing the new scene leaves s alone. Animations that
+// target flattened nodes no longer have an effect. opts may be nil.
+func (s *Scene) Flatten(opts *FlattenOptions) *Scene {
+
+	if opts == nil {
+		opts = &FlattenOptions{}
+	}
+
+	out := &Scene{
+		Flags:      s.Flags,
+		Name:       s.Name,
+		Materials:  append([]*Material(nil), s.Materials...),
+		Textures:   append([]*EmbeddedTexture(nil), s.Textures...),
+		Animations: make([]*Animation, len(s.Animations)),
+		Lights:     make([]*Light, len(s.Lights)),
+		Cameras:    make([]*Camera, len(s.Cameras)),
+		Metadata:   cloneMetadata(s.Metadata),
+		unitScaled: s.unitScaled,
+		Warnings:   append([]string(nil), s.Warnings...),
+		Meshes:     []*Mesh{},
+	}
+
+	for i, a := range s.Animations {
+		out.Animations[i] = a.clone()
+	}
+	for i, l := range s.Lights {
+		c := *l
+		out.Lights[i] = &c
+	}
+	for i, cam := range s.Cameras {
+		c := *cam
+		out.Cameras[i] = &c
+	}
+
+	if s.RootNode == nil {
+		return out
+	}
+
+	ident := mat4.Ident
+	root := &Node{
+		Name:           s.RootNode.Name,
+		Transformation: &ident,
+		Children:       []*Node{},
+		MeshIndicies:   []uint{},
+		Metadata:       cloneMetadata(s.RootNode.Metadata),
+	}
+	out.RootNode = root
+
+	var baked []*Mesh
+	var kept []*Node
+
+	s.Walk(func(n *Node, world mat4.T) error {
+
+		if n != s.RootNode && opts.Keep != nil && opts.Keep(n) {
+			kept = append(kept, n)
+			return SkipSubtree
+		}
+
+		for _, mi := range n.MeshIndicies {
+			if mi < uint(len(s.Meshes)) && s.Meshes[mi] != nil {
+				baked = append(baked, bakeMesh(s.Meshes[mi], &world))
+			}
+		}
+
+		return nil
+	})
+
+	if opts.MergeByMaterial {
+		baked = mergeMeshesByMaterial(baked, s.Materials)
+	}
+
+	for _, m := range baked {
+		root.MeshIndicies = append(root.MeshIndicies, uint(len(out.Meshes)))
+		out.Meshes = append(out.Meshes, m)
+	}
+
+	// Kept subtrees share one copy of every mesh they reference.
+	remap := map[uint]uint{}
+	for _, n := range kept {
+		c := copyNodeTree(n, root, s, out, remap)
+		world := n.WorldTransform()
+		c.Transformation = &world
+		root.Children = append(root.Children, c)
+	}
+
+	return out
+}
+
+// copyNodeTree copies n and its descendants below parent, copying the meshes
+// they reference from src to dst.
+func copyNodeTree(n *Node, parent *Node, src *Scene, dst *Scene, remap map[uint]uint) *Node {
+
+	c := &Node{
+		Name:         n.Name,
+		Parent:       parent,
+		Children:     []*Node{},
+		MeshIndicies: []uint{},
+		Metadata:     cloneMetadata(n.Metadata),
+	}
+
+	if n.Transformation != nil {
+		t := *n.Transformation
+		c.Transformation = &t
+	}
+
+	for _, mi := range n.MeshIndicies {
+
+		if mi >= uint(len(src.Meshes)) || src.Meshes[mi] == nil {
+			continue
+		}
+
+		ni, ok := remap[mi]
+		if !ok {
+			ni = uint(len(dst.Meshes))
+			dst.Meshes = append(dst.Meshes, bakeMesh(src.Meshes[mi], &mat4.Ident))
+			remap[mi] = ni
+		}
+
+		c.MeshIndicies = append(c.MeshIndicies, ni)
+	}
+
+	for _, child := range n.Children {
+		if child != nil {
+			c.Children = append(c.Children, copyNodeTree(child, c, src, dst, remap))
+		}
+	}
+
+	return c
+}
+
+// transformPoint applies the affine transform m to p.
+func transformPoint(m *mat4.T, p vec3.T) vec3.T {
+	return vec3.T{
+		m[0][0]*p[0] + m[1][0]*p[1] + m[2][0]*p[2] + m[3][0],
+		m[0][1]*p[0] + m[1][1]*p[1] + m[2][1]*p[2] + m[3][1],
+		m[0][2]*p[0] + m[1][2]*p[1] + m[2][2]*p[2] + m[3][2],
+	}
+}
+
+// transformDir applies the upper 3x3 of m to d.
+func transformDir(m *mat4.T, d vec3.T) vec3.T {
+	return vec3.T{
+		m[0][0]*d[0] + m[1][0]*d[1] + m[2][0]*d[2],
+		m[0][1]*d[0] + m[1][1]*d[1] + m[2][1]*d[2],
+		m[0][2]*d[0] + m[1][2]*d[1] + m[2][2]*d[2],
+	}
+}
+
+// normalMatrix returns the inverse-transpose of the upper 3x3 of m, stored
+// in a mat4.T for use with transformDir, and the determinant of the 3x3.
+func normalMatrix(m *mat4.T) (mat4.T, float32) {
+
+	det := m.Determinant3x3()
+
+	// The cofactor matrix is det * inverse-transpose.
+	var n mat4.T
+	for c := 0; c < 3; c++ {
+		for r := 0; r < 3; r++ {
+			c1, c2 := (c+1)%3, (c+2)%3
+			r1, r2 := (r+1)%3, (r+2)%3
+			n[c][r] = m[c1][r1]*m[c2][r2] - m[c1][r2]*m[c2][r1]
+		}
+	}
+
+	if det != 0 {
+		n.Mul(1 / det)
+	}
+	n[3][3] = 1
+
+	return n, det
+}
+
+func transformPoints(m *mat4.T, in []vec3.T) []vec3.T {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]vec3.T, len(in))
+	for i, p := range in {
+		out[i] = transformPoint(m, p)
+	}
+	return out
+}
+
+func transformDirs(m *mat4.T, in []vec3.T) []vec3.T {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]vec3.T, len(in))
+	for i, d := range in {
+		out[i] = transformDir(m, d)
+		out[i].Normalize()
+	}
+	return out
+}
+
+func cloneVec3s(in []vec3.T) []vec3.T {
+	if in == nil {
+		return nil
+	}
+	return append([]vec3.T{}, in...)
+}
+
+func cloneVec4s(in []vec4.T) []vec4.T {
+	if in == nil {
+		return nil
+	}
+	return append([]vec4.T{}, in...)
+}
+
+// bakeMesh returns a Go-owned copy of m with world applied.
+func bakeMesh(m *Mesh, world *mat4.T) *Mesh {
+
+	nm, det := normalMatrix(world)
+	mirrored := det < 0
+
+	out := &Mesh{
+		PrimitiveTypes:       m.PrimitiveTypes,
+		Vertices:             transformPoints(world, m.Vertices),
+		Normals:              transformDirs(&nm, m.Normals),
+		Tangents:             transformDirs(world, m.Tangents),
+		BitTangents:          transformDirs(world, m.BitTangents),
+		TexCoordChannelCount: m.TexCoordChannelCount,
+		TexCoordNames:        m.TexCoordNames,
+		Faces:                m.CopyFaces(),
+		Bones:                []*Bone{},
+		AnimMeshes:           []*AnimMesh{},
+		MorphMethod:          m.MorphMethod,
+		MaterialIndex:        m.MaterialIndex,
+		Name:                 m.Name,
+	}
+
+	for i := range m.ColorSets {
+		out.ColorSets[i] = cloneVec4s(m.ColorSets[i])
+	}
+	for i := range m.TexCoords {
+		out.TexCoords[i] = cloneVec3s(m.TexCoords[i])
+	}
+
+	if mirrored {
+		reverseWinding(out)
+	}
+
+	// Bone offsets map from mesh space to bone space, so the baked
+	// transform has to be undone first.
+	inv := world.Inverted()
+	for _, b := range m.Bones {
+		ob := &Bone{
+			Name:    b.Name,
+			Weights: append([]VertexWeight(nil), b.Weights...),
+		}
+		ob.OffsetMatrix.AssignMul(&b.OffsetMatrix, &inv)
+		out.Bones = append(out.Bones, ob)
+	}
+
+	for _, am := range m.AnimMeshes {
+		oam := &AnimMesh{
+			Name:        am.Name,
+			Vertices:    transformPoints(world, am.Vertices),
+			Normals:     transformDirs(&nm, am.Normals),
+			Tangents:    transformDirs(world, am.Tangents),
+			BitTangents: transformDirs(world, am.BitTangents),
+			Weight:      am.Weight,
+		}
+		for i := range am.Colors {
+			oam.Colors[i] = cloneVec4s(am.Colors[i])
+		}
+		for i := range am.TexCoords {
+			oam.TexCoords[i] = cloneVec3s(am.TexCoords[i])
+		}
+		out.AnimMeshes = append(out.AnimMeshes, oam)
+	}
+
+	out.AABB = out.ComputeAABB()
+
+	return out
+}
+
+// mergeMeshesByMaterial merges meshes with the same material index, in
+// order of first appearance.
+func mergeMeshesByMaterial(meshes []*Mesh, materials []*Material) []*Mesh {
+
+	var order []uint
+	groups := map[uint][]*Mesh{}
+
+	for _, m := range meshes {
+		if _, ok := groups[m.MaterialIndex]; !ok {
+			order = append(order, m.MaterialIndex)
+		}
+		groups[m.MaterialIndex] = append(groups[m.MaterialIndex], m)
+	}
+
+	merged := make([]*Mesh, 0, len(order))
+	for _, mi := range order {
+
+		group := groups[mi]
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+
+		m := mergeMeshes(group)
+
+		if mi < uint(len(materials)) && materials[mi] != nil {
+			if p := materials[mi].findProperty(matKeyName, 0, 0); p != nil {
+				if name, ok := p.stringValue(); ok {
+					m.Name = name
+				}
+			}
+		}
+
+		merged = append(merged, m)
+	}
+
+	return merged
+}
+
+// mergeMeshes concatenates meshes. An attribute is kept only if every mesh
+// has it.
+func mergeMeshes(meshes []*Mesh) *Mesh {
+
+	first := meshes[0]
+
+	out := &Mesh{
+		TexCoordChannelCount: first.TexCoordChannelCount,
+		TexCoordNames:        first.TexCoordNames,
+		Bones:                []*Bone{},
+		AnimMeshes:           []*AnimMesh{},
+		MorphMethod:          first.MorphMethod,
+		MaterialIndex:        first.MaterialIndex,
+		Name:                 first.Name,
+	}
+
+	all := func(has func(m *Mesh) bool) bool {
+		for _, m := range meshes {
+			if !has(m) {
+				return false
+			}
+		}
+		return true
+	}
+
+	hasNormals := all(func(m *Mesh) bool { return len(m.Normals) == len(m.Vertices) && len(m.Normals) > 0 })
+	hasTangents := all(func(m *Mesh) bool {
+		return len(m.Tangents) == len(m.Vertices) && len(m.BitTangents) == len(m.Vertices) && len(m.Tangents) > 0
+	})
+
+	var hasColors [MaxColorSets]bool
+	for i := range hasColors {
+		hasColors[i] = all(func(m *Mesh) bool { return len(m.ColorSets[i]) == len(m.Vertices) && len(m.ColorSets[i]) > 0 })
+	}
+
+	var hasTexCoords [MaxTexCoords]bool
+	for i := range hasTexCoords {
+		hasTexCoords[i] = all(func(m *Mesh) bool { return len(m.TexCoords[i]) == len(m.Vertices) && len(m.TexCoords[i]) > 0 })
+		if !hasTexCoords[i] {
+			out.TexCoordChannelCount[i] = 0
+			out.TexCoordNames[i] = ""
+		}
+	}
+
+	for _, m := range meshes {
+
+		base := uint(len(out.Vertices))
+
+		out.PrimitiveTypes |= m.PrimitiveTypes
+		out.Vertices = append(out.Vertices, m.Vertices...)
+
+		if hasNormals {
+			out.Normals = append(out.Normals, m.Normals...)
+		}
+		if hasTangents {
+			out.Tangents = append(out.Tangents, m.Tangents...)
+			out.BitTangents = append(out.BitTangents, m.BitTangents...)
+		}
+		for i := range hasColors {
+			if hasColors[i] {
+				out.ColorSets[i] = append(out.ColorSets[i], m.ColorSets[i]...)
+			}
+		}
+		for i := range hasTexCoords {
+			if hasTexCoords[i] {
+				out.TexCoords[i] = append(out.TexCoords[i], m.TexCoords[i]...)
+			}
+		}
+
+		for _, f := range m.Faces {
+			indices := make([]uint, len(f.Indices))
+			for j, idx := range f.Indices {
+				indices[j] = idx + base
+			}
+			out.Faces = append(out.Faces, Face{Indices: indices})
+		}
+	}
+
+	out.AABB = out.ComputeAABB()
+
+	return out
+}
@@ -0,0 +1,259 @@
+package assimp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec3"
+)
+
+// triangleMesh 构建一个带法线的三角形网格
+func triangleMesh(name string, material uint) *Mesh {
+	return &Mesh{
+		Name:          name,
+		Vertices:      []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		Normals:       []vec3.T{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		Faces:         []Face{{Indices: []uint{0, 1, 2}}},
+		MaterialIndex: material,
+	}
+}
+
+func approxVec3(a, b vec3.T) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-5 {
+			return false
+		}
+	}
+	return true
+}
+
+// buildFlattenScene 构建测试场景:
+// root(平移1,0,0) -> scaled(缩放2,1,1, 网格0), root -> mirrored(缩放-1,1,1, 网格0和1)
+func buildFlattenScene() *Scene {
+	rootT := mat4.Ident
+	rootT.SetTranslation(&vec3.T{1, 0, 0})
+	scaledT := mat4.Ident
+	scaledT.ScaleVec3(&vec3.T{2, 1, 1})
+	mirroredT := mat4.Ident
+	mirroredT.ScaleVec3(&vec3.T{-1, 1, 1})
+
+	root := &Node{Name: "root", Transformation: &rootT}
+	scaled := &Node{Name: "scaled", Parent: root, Transformation: &scaledT, MeshIndicies: []uint{0}}
+	mirrored := &Node{Name: "mirrored", Parent: root, Transformation: &mirroredT, MeshIndicies: []uint{0, 1}}
+	root.Children = []*Node{scaled, mirrored}
+
+	tilted := triangleMesh("tilted", 0)
+	tilted.Normals = []vec3.T{{0.6, 0, 0.8}, {0.6, 0, 0.8}, {0.6, 0, 0.8}}
+
+	return &Scene{
+		RootNode: root,
+		Meshes:   []*Mesh{triangleMesh("tri", 0), tilted},
+		Materials: []*Material{{Properties: []*MaterialProperty{
+			aiStringProperty(matKeyName, TextureTypeNone, 0, "steel"),
+		}}},
+	}
+}
+
+// TestFlattenBakesTransforms 测试世界变换被烘焙到顶点和法线中
+func TestFlattenBakesTransforms(t *testing.T) {
+	scene := buildFlattenScene()
+	flat := scene.Flatten(nil)
+
+	if flat.RootNode == nil || *flat.RootNode.Transformation != mat4.Ident {
+		t.Fatal("Expected flattened root with identity transformation")
+	}
+	if len(flat.RootNode.Children) != 0 || len(flat.RootNode.MeshIndicies) != 3 || len(flat.Meshes) != 3 {
+		t.Fatalf("Expected 3 baked meshes on the root, got %d meshes", len(flat.Meshes))
+	}
+
+	scaled := flat.Meshes[0]
+	if scaled.Vertices[1] != (vec3.T{3, 0, 0}) || scaled.Vertices[2] != (vec3.T{1, 1, 0}) {
+		t.Errorf("Expected vertices scaled and translated, got %v", scaled.Vertices)
+	}
+	if scaled.AABB.Min != (vec3.T{1, 0, 0}) || scaled.AABB.Max != (vec3.T{3, 1, 0}) {
+		t.Errorf("Expected recomputed AABB, got %v", scaled.AABB)
+	}
+	if scaled.Faces[0].Indices[1] != 1 {
+		t.Error("Expected winding to be kept for a non-mirrored transform")
+	}
+
+	// 来自被缩放网格的原始数据不应被修改
+	if scene.Meshes[0].Vertices[1] != (vec3.T{1, 0, 0}) {
+		t.Error("Expected source mesh to be left unchanged")
+	}
+
+	tilted := flat.Meshes[2]
+	if !approxVec3(tilted.Normals[0], vec3.T{-0.6, 0, 0.8}) {
+		t.Errorf("Expected mirrored normal (-0.6,0,0.8), got %v", tilted.Normals[0])
+	}
+}
+
+// TestFlattenNonUniformNormals 测试非均匀缩放时法线仍垂直于表面
+func TestFlattenNonUniformNormals(t *testing.T) {
+	m := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, -1, 0}, {0, 0, 1}},
+		Normals:  []vec3.T{{0.70710677, 0.70710677, 0}, {0.70710677, 0.70710677, 0}, {0.70710677, 0.70710677, 0}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}},
+	}
+
+	world := mat4.Ident
+	world.ScaleVec3(&vec3.T{3, 1, 1})
+	baked := bakeMesh(m, &world)
+
+	edge := vec3.Sub(&baked.Vertices[1], &baked.Vertices[0])
+	if d := vec3.Dot(&edge, &baked.Normals[0]); math.Abs(float64(d)) > 1e-5 {
+		t.Errorf("Expected normal perpendicular to the baked surface, dot %v", d)
+	}
+	if l := baked.Normals[0].Length(); math.Abs(float64(l-1)) > 1e-5 {
+		t.Errorf("Expected unit normal, got length %v", l)
+	}
+}
+
+// TestFlattenMirrored 测试镜像变换会翻转面的顶点顺序
+func TestFlattenMirrored(t *testing.T) {
+	scene := buildFlattenScene()
+	flat := scene.Flatten(nil)
+
+	mirrored := flat.Meshes[1]
+	if mirrored.Vertices[1] != (vec3.T{0, 0, 0}) {
+		t.Errorf("Expected (1,0,0) mirrored and translated to (0,0,0), got %v", mirrored.Vertices[1])
+	}
+
+	f := mirrored.Faces[0].Indices
+	if f[0] != 2 || f[1] != 1 || f[2] != 0 {
+		t.Errorf("Expected reversed winding, got %v", f)
+	}
+
+	// 顶点顺序翻转后几何法线应与烘焙后的法线同向
+	e1 := vec3.Sub(&mirrored.Vertices[f[1]], &mirrored.Vertices[f[0]])
+	e2 := vec3.Sub(&mirrored.Vertices[f[2]], &mirrored.Vertices[f[0]])
+	geom := vec3.Cross(&e1, &e2)
+	if vec3.Dot(&geom, &mirrored.Normals[0]) <= 0 {
+		t.Errorf("Expected face normal %v to agree with vertex normal %v", geom, mirrored.Normals[0])
+	}
+
+	// 源网格的面不应被修改
+	if scene.Meshes[0].Faces[0].Indices[0] != 0 {
+		t.Error("Expected source faces to be left unchanged")
+	}
+}
+
+// TestFlattenMergeByMaterial 测试按材质合并网格
+func TestFlattenMergeByMaterial(t *testing.T) {
+	scene := buildFlattenScene()
+	scene.Meshes[0].TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	scene.Meshes[0].TexCoordChannelCount[0] = 2
+
+	flat := scene.Flatten(&FlattenOptions{MergeByMaterial: true})
+
+	if len(flat.Meshes) != 1 || len(flat.RootNode.MeshIndicies) != 1 {
+		t.Fatalf("Expected a single merged mesh, got %d", len(flat.Meshes))
+	}
+
+	m := flat.Meshes[0]
+	if m.Name != "steel" {
+		t.Errorf("Expected merged mesh named after its material, got %q", m.Name)
+	}
+	if len(m.Vertices) != 9 || len(m.Faces) != 3 || len(m.Normals) != 9 {
+		t.Fatalf("Expected 9 vertices, 9 normals and 3 faces, got %d, %d, %d", len(m.Vertices), len(m.Normals), len(m.Faces))
+	}
+	if m.Faces[1].Indices[0] != 5 || m.Faces[2].Indices[2] != 6 {
+		t.Errorf("Expected face indices offset by the merged vertex count, got %v", m.Faces)
+	}
+
+	// 只有部分网格有UV, 合并后丢弃
+	if m.TexCoords[0] != nil || m.TexCoordChannelCount[0] != 0 {
+		t.Error("Expected texture coordinates missing from some meshes to be dropped")
+	}
+	if m.AABB.Min != (vec3.T{0, 0, 0}) || m.AABB.Max != (vec3.T{3, 1, 0}) {
+		t.Errorf("Expected merged AABB, got %v", m.AABB)
+	}
+}
+
+// TestFlattenKeep 测试保留的节点不被展平
+func TestFlattenKeep(t *testing.T) {
+	scene := buildFlattenScene()
+	flat := scene.Flatten(&FlattenOptions{Keep: func(n *Node) bool { return n.Name == "mirrored" }})
+
+	if len(flat.RootNode.MeshIndicies) != 1 || len(flat.RootNode.Children) != 1 {
+		t.Fatalf("Expected 1 baked mesh and 1 kept node, got %d and %d", len(flat.RootNode.MeshIndicies), len(flat.RootNode.Children))
+	}
+
+	kept := flat.RootNode.Children[0]
+	if kept.Name != "mirrored" || kept.Parent != flat.RootNode || kept == scene.FindNode("mirrored") {
+		t.Fatal("Expected a copy of the kept node below the new root")
+	}
+	if *kept.Transformation != scene.FindNode("mirrored").WorldTransform() {
+		t.Error("Expected kept node to carry its world transform")
+	}
+	if len(kept.MeshIndicies) != 2 || len(flat.Meshes) != 3 {
+		t.Fatalf("Expected kept meshes to be copied, got %v", kept.MeshIndicies)
+	}
+
+	m := flat.Meshes[kept.MeshIndicies[0]]
+	if m == scene.Meshes[0] || m.Vertices[1] != (vec3.T{1, 0, 0}) {
+		t.Error("Expected kept meshes to be copied unbaked")
+	}
+
+	if issues := flat.Validate(); len(issues) != 0 {
+		t.Errorf("Expected a valid flattened scene, got %v", issues)
+	}
+}
+
+// TestFlattenCopiesAnimationsLightsCameras 测试修改展平后的动画、灯光和相机不影响原场景
+func TestFlattenCopiesAnimationsLightsCameras(t *testing.T) {
+	scene := buildFlattenScene()
+	scene.Animations = []*Animation{{
+		name:         "walk",
+		channels:     []*NodeAnim{{name: "scaled", positionKeys: []VectorKey{{time: 0, value: vec3.T{1, 2, 3}}}}},
+		meshChannels: []*MeshAnim{{name: "tri", keys: []MeshKey{{time: 0, value: 1}}}},
+	}}
+	scene.Lights = []*Light{{name: "sun", position: vec3.T{0, 5, 0}}}
+	scene.Cameras = []*Camera{{name: "main", clipPlaneFar: 100}}
+
+	out := scene.Flatten(nil)
+
+	if out.Animations[0].Name() != "walk" || out.Lights[0].Name() != "sun" || out.Cameras[0].Name() != "main" {
+		t.Fatal("Expected animations, lights and cameras to be copied")
+	}
+
+	out.Animations[0].channels[0].positionKeys[0].value = vec3.T{}
+	out.Animations[0].meshChannels[0].keys[0].value = 0
+	out.Lights[0].position = vec3.T{}
+	out.Cameras[0].clipPlaneFar = 1
+
+	if scene.Animations[0].channels[0].positionKeys[0].value != (vec3.T{1, 2, 3}) || scene.Animations[0].meshChannels[0].keys[0].value != 1 {
+		t.Error("Expected the source animation keys to be unchanged")
+	}
+	if scene.Lights[0].position != (vec3.T{0, 5, 0}) || scene.Cameras[0].clipPlaneFar != 100 {
+		t.Error("Expected the source light and camera to be unchanged")
+	}
+}
+
+// TestFlattenCopiesMetadata 测试场景和节点的元数据连同嵌套的元数据一起被复制
+func TestFlattenCopiesMetadata(t *testing.T) {
+	scene := buildFlattenScene()
+	scene.Metadata = map[string]Metadata{
+		"Pset_WallCommon": {Type: MetadataTypeMetadata, Value: map[string]Metadata{
+			"FireRating": {Type: MetadataTypeString, Value: "F90"},
+		}},
+	}
+	scene.RootNode.Metadata = map[string]Metadata{
+		"Props": {Type: MetadataTypeMetadata, Value: map[string]Metadata{
+			"Level": {Type: MetadataTypeInt32, Value: int32(2)},
+		}},
+	}
+
+	out := scene.Flatten(nil)
+
+	out.Metadata["Pset_WallCommon"].Value.(map[string]Metadata)["FireRating"] = Metadata{Type: MetadataTypeString, Value: "F30"}
+	out.RootNode.Metadata["Props"].Value.(map[string]Metadata)["Level"] = Metadata{Type: MetadataTypeInt32, Value: int32(0)}
+
+	if v, _ := GetMeta[string](scene.Metadata, "Pset_WallCommon/FireRating"); v != "F90" {
+		t.Errorf("Expected the source scene metadata to be unchanged, got %q", v)
+	}
+	if v, _ := GetMeta[int32](scene.RootNode.Metadata, "Props/Level"); v != 2 {
+		t.Errorf("Expected the source node metadata to be unchanged, got %d", v)
+	}
+}
@@ -12,5 +12,3 @@ require (
 )
 
 require github.com/flywave/gltf v0.20.4-0.20250828104044-ebb99e75f3cc // indirect
-
-replace github.com/flywave/go-mst => ../go-mst
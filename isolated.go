@@ -0,0 +1,213 @@
+package assimp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// isolatedEnv marks a process started by ImportIsolated, which serves a
+// single import from ServeIsolatedImport.
+const isolatedEnv = "ASSIMP_ISOLATED_IMPORT"
+
+// isolatedResponseFD is the file descriptor the child writes its response
+// to. Stdout is left alone since the program may print to it.
+const isolatedResponseFD = 3
+
+func init() {
+	// Metadata values are sent as interface values.
+	gob.Register(vec3.T{})
+	gob.Register(map[string]Metadata{})
+}
+
+// ServeIsolatedImport serves the import of a process started by
+// ImportIsolated and exits. In any other process it returns immediately.
+// The binary passed as IsolatedOptions.Executable calls it first thing in
+// main, or in TestMain for tests:
+//
+//	func main() {
+//		assimp.ServeIsolatedImport()
+//		...
+//	}
+func ServeIsolatedImport() {
+
+	if os.Getenv(isolatedEnv) == "" {
+		return
+	}
+
+	os.Unsetenv(isolatedEnv)
+	os.Exit(serveIsolatedImport(os.Stdin, os.NewFile(isolatedResponseFD, "response")))
+}
+
+// IsolatedOptions configures ImportIsolated.
+type IsolatedOptions struct {
+	// Executable is the binary that runs the import and is required. It
+	// must call ServeIsolatedImport before doing anything else, e.g. a small
+	// helper command, or os.Executable() if the program's own main does.
+	Executable string
+	// MemoryLimit caps the address space of the import process in bytes.
+	// Zero means no limit. Only supported on Linux and macOS.
+	MemoryLimit uint64
+}
+
+// ImportIsolated imports file in a child process, so an importer that
+// crashes or hangs on malformed input can't take down the caller. The child
+// runs opts.Executable, imports the file and sends the scene back over a
+// pipe. The executable must call ServeIsolatedImport at the start of main.
+//
+// Cancelling ctx kills the child; the returned ImportError then wraps
+// ctx.Err(). A child that crashes, or is killed for exceeding
+// opts.MemoryLimit, yields an ImportError wrapping ErrImportCrashed.
+//
+// The returned scene is Go-owned and holds no imported data, so it can't be
+// post-processed with ApplyPostProcessing.
+func ImportIsolated(ctx context.Context, file string, postProcessFlags PostProcess, opts *IsolatedOptions) (*Scene, error) {
+
+	// Re-executing an arbitrary program would run its whole main, so
+	// the executable has to be named explicitly.
+	if opts == nil || opts.Executable == "" {
+		return nil, errors.New("asig error: isolated import: no executable set")
+	}
+	exe := opts.Executable
+
+	var req bytes.Buffer
+	if err := gob.NewEncoder(&req).Encode(&wireRequest{Path: file, Flags: postProcessFlags, MemoryLimit: opts.MemoryLimit}); err != nil {
+		return nil, fmt.Errorf("asig error: isolated import: %w", err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("asig error: isolated import: %w", err)
+	}
+	defer r.Close()
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Env = append(os.Environ(), isolatedEnv+"=1")
+	cmd.Stdin = &req
+	cmd.Stderr = &stderr
+	cmd.ExtraFiles = []*os.File{w}
+
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("asig error: isolated import: %w", err)
+	}
+
+	var resp wireResponse
+	decodeErr := gob.NewDecoder(bufio.NewReader(r)).Decode(&resp)
+	waitErr := cmd.Wait()
+
+	hint := strings.TrimPrefix(filepath.Ext(file), ".")
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, &ImportError{Path: file, Hint: hint, Message: "isolated import: " + ctxErr.Error(), Err: ctxErr}
+	}
+
+	if waitErr != nil || decodeErr != nil {
+		msg := "import process crashed"
+		if waitErr != nil {
+			msg += ": " + waitErr.Error()
+		}
+		if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+			msg += ": " + line
+		}
+		return nil, &ImportError{Path: file, Hint: hint, Message: msg, Err: ErrImportCrashed}
+	}
+
+	if resp.Err != nil {
+		return nil, &ImportError{Path: resp.Err.Path, Hint: resp.Err.Hint, Message: resp.Err.Message, Err: errorFromKind(resp.Err.Kind)}
+	}
+
+	if resp.Scene == nil {
+		return nil, &ImportError{Path: file, Hint: hint, Message: "import process returned no scene", Err: ErrImportCrashed}
+	}
+
+	s := resp.Scene.scene()
+	s.unitScaled = postProcessFlags&PostProcessGlobalScale != 0
+	return s, nil
+}
+
+// serveIsolatedImport runs the import requested on in and writes the
+// response to out. It returns the exit code of the child process.
+func serveIsolatedImport(in *os.File, out *os.File) int {
+
+	defer out.Close()
+
+	var req wireRequest
+	if err := gob.NewDecoder(in).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "assimp: reading isolated import request:", err)
+		return 2
+	}
+
+	resp := isolatedImport(&req)
+
+	bw := bufio.NewWriter(out)
+	if err := gob.NewEncoder(bw).Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, "assimp: writing isolated import response:", err)
+		return 2
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "assimp: writing isolated import response:", err)
+		return 2
+	}
+
+	return 0
+}
+
+func isolatedImport(req *wireRequest) *wireResponse {
+
+	if req.MemoryLimit > 0 {
+		if err := setMemoryLimit(req.MemoryLimit); err != nil {
+			return &wireResponse{Err: &wireError{Path: req.Path, Message: "setting memory limit: " + err.Error()}}
+		}
+	}
+
+	s, release, err := ImportFile(req.Path, req.Flags)
+	defer release()
+
+	if err != nil {
+		var ie *ImportError
+		if !errors.As(err, &ie) {
+			return &wireResponse{Err: &wireError{Path: req.Path, Message: err.Error()}}
+		}
+		return &wireResponse{Err: &wireError{Path: ie.Path, Hint: ie.Hint, Message: ie.Message, Kind: errorKind(ie.Err)}}
+	}
+
+	return &wireResponse{Scene: toWireScene(s)}
+}
+
+// errorKinds names the sentinel errors an ImportError can wrap, so they
+// survive the trip from the child process.
+var errorKinds = map[string]error{
+	"file-not-found":     ErrFileNotFound,
+	"unsupported-format": ErrUnsupportedFormat,
+	"parse-failed":       ErrParseFailed,
+	"validation-failed":  ErrValidationFailed,
+}
+
+func errorKind(err error) string {
+	for kind, e := range errorKinds {
+		if e == err {
+			return kind
+		}
+	}
+	return ""
+}
+
+func errorFromKind(kind string) error {
+	if err, ok := errorKinds[kind]; ok {
+		return err
+	}
+	return ErrParseFailed
+}
@@ -0,0 +1,12 @@
+//go:build linux || darwin
+
+package assimp
+
+import "syscall"
+
+// setMemoryLimit caps the address space of the current process. Allocations
+// beyond it fail, which Assimp reports as an import error or, outside its
+// exception handling, terminates the process.
+func setMemoryLimit(limit uint64) error {
+	return syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: limit, Max: limit})
+}
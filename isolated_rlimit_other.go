@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package assimp
+
+import "errors"
+
+func setMemoryLimit(limit uint64) error {
+	return errors.New("memory limits are not supported on this platform")
+}
@@ -0,0 +1,205 @@
+package assimp
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/quaternion"
+	"github.com/flywave/go3d/vec3"
+)
+
+// TestMain 让ImportIsolated重新执行的测试二进制处理子进程导入
+func TestMain(m *testing.M) {
+	ServeIsolatedImport()
+	os.Exit(m.Run())
+}
+
+// TestWireSceneRoundTrip 测试场景经gob编码往返后保持不变
+func TestWireSceneRoundTrip(t *testing.T) {
+	root := &Node{Name: "root", Transformation: &mat4.Ident, Metadata: map[string]Metadata{
+		"UnitScaleFactor": {Type: MetadataTypeFloat64, Value: 100.0},
+		"Offset":          {Type: MetadataTypeVec3, Value: vec3.T{1, 2, 3}},
+	}}
+	child := &Node{Name: "child", Parent: root, MeshIndicies: []uint{0}}
+	grandChild := &Node{Name: "grandchild", Parent: child}
+	child.Children = []*Node{grandChild}
+	root.Children = []*Node{child, {Name: "sibling", Parent: root}}
+
+	scene := &Scene{
+		Flags:    SceneFlagValidated,
+		Name:     "level",
+		RootNode: root,
+		Meshes: []*Mesh{{
+			Name:     "tri",
+			Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+			Faces:    []Face{{Indices: []uint{0, 1, 2}}},
+		}},
+		Materials: []*Material{{Properties: []*MaterialProperty{aiStringProperty(matKeyTextureBase, TextureTypeDiffuse, 0, "albedo.png")}}},
+		Animations: []*Animation{{
+			name:     "walk",
+			duration: 2,
+			channels: []*NodeAnim{{
+				name:         "child",
+				positionKeys: []VectorKey{{time: 1, value: vec3.T{1, 2, 3}}},
+				rotationKeys: []QuatKey{{time: 1, value: quaternion.Ident}},
+			}},
+			meshChannels: []*MeshAnim{{name: "tri", keys: []MeshKey{{time: 0.5, value: 1}}}},
+		}},
+		Lights:   []*Light{{name: "sun", typ: LightSource_Directional, direction: vec3.T{0, -1, 0}}},
+		Cameras:  []*Camera{{name: "main", aspect: 1.5}},
+		Metadata: map[string]Metadata{"UpAxis": {Type: MetadataTypeInt32, Value: int32(2)}},
+		Warnings: []string{"something odd"},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(toWireScene(scene)); err != nil {
+		t.Fatalf("Unexpected encode error: %v", err)
+	}
+
+	var ws wireScene
+	if err := gob.NewDecoder(&buf).Decode(&ws); err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	got := ws.scene()
+
+	if got.Flags != SceneFlagValidated || got.Name != "level" || len(got.Warnings) != 1 {
+		t.Error("Expected flags, name and warnings to survive the round trip")
+	}
+
+	if got.Metadata["UpAxis"].Value != int32(2) {
+		t.Errorf("Expected scene metadata to survive the round trip, got %v", got.Metadata)
+	}
+
+	if got.RootNode.Name != "root" || len(got.RootNode.Children) != 2 {
+		t.Fatalf("Expected root with 2 children, got %+v", got.RootNode)
+	}
+	if *got.RootNode.Transformation != mat4.Ident {
+		t.Error("Expected root transformation to survive the round trip")
+	}
+	if got.RootNode.Metadata["Offset"].Value != (vec3.T{1, 2, 3}) || got.RootNode.Metadata["UnitScaleFactor"].Value != 100.0 {
+		t.Errorf("Expected metadata to survive the round trip, got %v", got.RootNode.Metadata)
+	}
+
+	gotChild := got.RootNode.Children[0]
+	if gotChild.Name != "child" || gotChild.Parent != got.RootNode || len(gotChild.MeshIndicies) != 1 {
+		t.Errorf("Unexpected child node %+v", gotChild)
+	}
+	if len(gotChild.Children) != 1 || gotChild.Children[0].Name != "grandchild" || gotChild.Children[0].Parent != gotChild {
+		t.Error("Expected grandchild to be linked to its parent")
+	}
+	if got.RootNode.Children[1].Name != "sibling" {
+		t.Error("Expected child order to be kept")
+	}
+
+	if len(got.Meshes) != 1 || len(got.Meshes[0].Vertices) != 3 || got.Meshes[0].Faces[0].Indices[2] != 2 {
+		t.Error("Expected meshes to survive the round trip")
+	}
+
+	if info, err := GetMaterialTexture(got.Materials[0], TextureTypeDiffuse, 0); err != nil || info.Path != "albedo.png" {
+		t.Errorf("Expected material texture albedo.png, got %v, %v", info, err)
+	}
+
+	anim := got.Animations[0]
+	if anim.Name() != "walk" || anim.Duration() != 2 {
+		t.Error("Expected animation to survive the round trip")
+	}
+	if anim.Channels()[0].PositionKeys()[0].Value() != (vec3.T{1, 2, 3}) || anim.Channels()[0].RotationKeys()[0].Value() != quaternion.Ident {
+		t.Error("Expected animation keys to survive the round trip")
+	}
+	if anim.MeshChannels()[0].Keys()[0].Value() != 1 {
+		t.Error("Expected mesh animation keys to survive the round trip")
+	}
+
+	if got.Lights[0].Name() != "sun" || got.Lights[0].Direction() != (vec3.T{0, -1, 0}) {
+		t.Error("Expected lights to survive the round trip")
+	}
+	if got.Cameras[0].Name() != "main" || got.Cameras[0].Aspect() != 1.5 {
+		t.Error("Expected cameras to survive the round trip")
+	}
+}
+
+// TestImportIsolatedFileNotFound 测试子进程中的导入错误会原样返回
+func TestImportIsolatedFileNotFound(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ImportIsolated(ctx, "nonexistent.obj", PostProcessTriangulate, &IsolatedOptions{Executable: exe})
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Fatalf("Expected ErrFileNotFound from the child process, got %v", err)
+	}
+
+	var ie *ImportError
+	if !errors.As(err, &ie) || ie.Path != "nonexistent.obj" {
+		t.Errorf("Expected ImportError for nonexistent.obj, got %v", err)
+	}
+}
+
+// TestImportIsolatedRequiresExecutable 测试未指定可执行文件时返回错误
+func TestImportIsolatedRequiresExecutable(t *testing.T) {
+	for _, opts := range []*IsolatedOptions{nil, {MemoryLimit: 1 << 30}} {
+		if _, err := ImportIsolated(context.Background(), "model.obj", 0, opts); err == nil {
+			t.Errorf("Expected an error without an executable, opts %+v", opts)
+		}
+	}
+}
+
+// writeScript 写入一个用于模拟子进程的shell脚本
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "child.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestImportIsolatedTimeout 测试子进程超时会被终止
+func TestImportIsolatedTimeout(t *testing.T) {
+	exe := writeScript(t, "exec sleep 30")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := ImportIsolated(ctx, "model.obj", 0, &IsolatedOptions{Executable: exe})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Error("Expected the child process to be killed on timeout")
+	}
+}
+
+// TestImportIsolatedCrash 测试子进程崩溃时返回ErrImportCrashed
+func TestImportIsolatedCrash(t *testing.T) {
+	exe := writeScript(t, "echo 'boom' >&2\nkill -SEGV $$")
+
+	_, err := ImportIsolated(context.Background(), "model.obj", 0, &IsolatedOptions{Executable: exe})
+	if !errors.Is(err, ErrImportCrashed) {
+		t.Fatalf("Expected ErrImportCrashed, got %v", err)
+	}
+
+	var ie *ImportError
+	if !errors.As(err, &ie) || ie.Hint != "obj" || !bytes.Contains([]byte(ie.Message), []byte("boom")) {
+		t.Errorf("Expected crash error with hint and stderr output, got %v", err)
+	}
+}
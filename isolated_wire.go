@@ -0,0 +1,380 @@
+package assimp
+
+import (
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/quaternion"
+	"github.com/flywave/go3d/vec3"
+)
+
+// The wire types carry a Scene from the isolated import process to its
+// parent with encoding/gob. gob only sees exported fields and can't encode
+// the Parent links of the node tree, so the scene is converted to these
+// flat mirrors first.
+
+type wireRequest struct {
+	Path        string
+	Flags       PostProcess
+	MemoryLimit uint64
+}
+
+type wireResponse struct {
+	Scene *wireScene
+	Err   *wireError
+}
+
+type wireError struct {
+	Path    string
+	Hint    string
+	Message string
+	Kind    string
+}
+
+type wireScene struct {
+	Flags SceneFlag
+	Name  string
+
+	// Nodes holds the node tree in depth-first order; Nodes[0] is the root.
+	Nodes      []wireNode
+	Meshes     []*Mesh
+	Materials  []wireMaterial
+	Textures   []*EmbeddedTexture
+	Animations []wireAnimation
+	Lights     []wireLight
+	Cameras    []wireCamera
+
+	Metadata map[string]Metadata
+	Warnings []string
+}
+
+type wireNode struct {
+	Name           string
+	Transformation *mat4.T
+	// Parent is the index of the parent node, -1 for the root.
+	Parent       int
+	MeshIndicies []uint
+	Metadata     map[string]Metadata
+}
+
+type wireMaterial struct {
+	Properties       []wireMaterialProperty
+	AllocatedStorage uint
+}
+
+type wireMaterialProperty struct {
+	Name     string
+	Semantic TextureType
+	Index    uint
+	TypeInfo MatPropertyTypeInfo
+	Data     []byte
+}
+
+type wireAnimation struct {
+	Name           string
+	Duration       float64
+	TicksPerSecond float64
+	Channels       []wireNodeAnim
+	MeshChannels   []wireMeshAnim
+}
+
+type wireNodeAnim struct {
+	Name         string
+	PositionKeys []wireVectorKey
+	RotationKeys []wireQuatKey
+	ScalingKeys  []wireVectorKey
+	PreState     AnimBehaviour
+	PostState    AnimBehaviour
+}
+
+type wireMeshAnim struct {
+	Name string
+	Keys []wireMeshKey
+}
+
+type wireVectorKey struct {
+	Time  float64
+	Value vec3.T
+}
+
+type wireQuatKey struct {
+	Time  float64
+	Value quaternion.T
+}
+
+type wireMeshKey struct {
+	Time  float64
+	Value int
+}
+
+type wireLight struct {
+	Name                 string
+	Type                 LightSourceType
+	Position             vec3.T
+	Direction            vec3.T
+	AttenuationConstant  float32
+	AttenuationLinear    float32
+	AttenuationQuadratic float32
+	ColorDiffuse         vec3.T
+	ColorSpecular        vec3.T
+	ColorAmbient         vec3.T
+	AngleInnerCone       float32
+	AngleOuterCone       float32
+}
+
+type wireCamera struct {
+	Name          string
+	Position      vec3.T
+	Up            vec3.T
+	LookAt        vec3.T
+	HorizontalFov float32
+	ClipPlaneNear float32
+	ClipPlaneFar  float32
+	Aspect        float32
+}
+
+func toWireScene(s *Scene) *wireScene {
+
+	ws := &wireScene{
+		Flags:    s.Flags,
+		Name:     s.Name,
+		Meshes:   s.Meshes,
+		Textures: s.Textures,
+		Metadata: s.Metadata,
+		Warnings: s.Warnings,
+	}
+
+	if s.RootNode != nil {
+		ws.Nodes = appendWireNodes(ws.Nodes, s.RootNode, -1)
+	}
+
+	for _, m := range s.Materials {
+		wm := wireMaterial{AllocatedStorage: m.AllocatedStorage}
+		for _, p := range m.Properties {
+			wm.Properties = append(wm.Properties, wireMaterialProperty{
+				Name:     p.name,
+				Semantic: p.Semantic,
+				Index:    p.Index,
+				TypeInfo: p.TypeInfo,
+				Data:     p.Data,
+			})
+		}
+		ws.Materials = append(ws.Materials, wm)
+	}
+
+	for _, a := range s.Animations {
+		ws.Animations = append(ws.Animations, toWireAnimation(a))
+	}
+
+	for _, l := range s.Lights {
+		ws.Lights = append(ws.Lights, wireLight{
+			Name:                 l.name,
+			Type:                 l.typ,
+			Position:             l.position,
+			Direction:            l.direction,
+			AttenuationConstant:  l.attenuationConstant,
+			AttenuationLinear:    l.attenuationLinear,
+			AttenuationQuadratic: l.attenuationQuadratic,
+			ColorDiffuse:         l.colorDiffuse,
+			ColorSpecular:        l.colorSpecular,
+			ColorAmbient:         l.colorAmbient,
+			AngleInnerCone:       l.angleInnerCone,
+			AngleOuterCone:       l.angleOuterCone,
+		})
+	}
+
+	for _, c := range s.Cameras {
+		ws.Cameras = append(ws.Cameras, wireCamera{
+			Name:          c.name,
+			Position:      c.position,
+			Up:            c.up,
+			LookAt:        c.lookAt,
+			HorizontalFov: c.horizontalFov,
+			ClipPlaneNear: c.clipPlaneNear,
+			ClipPlaneFar:  c.clipPlaneFar,
+			Aspect:        c.aspect,
+		})
+	}
+
+	return ws
+}
+
+func appendWireNodes(nodes []wireNode, n *Node, parent int) []wireNode {
+
+	index := len(nodes)
+	nodes = append(nodes, wireNode{
+		Name:           n.Name,
+		Transformation: n.Transformation,
+		Parent:         parent,
+		MeshIndicies:   n.MeshIndicies,
+		Metadata:       n.Metadata,
+	})
+
+	for _, c := range n.Children {
+		nodes = appendWireNodes(nodes, c, index)
+	}
+
+	return nodes
+}
+
+func toWireAnimation(a *Animation) wireAnimation {
+
+	wa := wireAnimation{
+		Name:           a.name,
+		Duration:       a.duration,
+		TicksPerSecond: a.ticksPerSecond,
+	}
+
+	for _, c := range a.channels {
+		wc := wireNodeAnim{
+			Name:      c.name,
+			PreState:  c.preState,
+			PostState: c.postState,
+		}
+		for _, k := range c.positionKeys {
+			wc.PositionKeys = append(wc.PositionKeys, wireVectorKey{Time: k.time, Value: k.value})
+		}
+		for _, k := range c.rotationKeys {
+			wc.RotationKeys = append(wc.RotationKeys, wireQuatKey{Time: k.time, Value: k.value})
+		}
+		for _, k := range c.scalingKeys {
+			wc.ScalingKeys = append(wc.ScalingKeys, wireVectorKey{Time: k.time, Value: k.value})
+		}
+		wa.Channels = append(wa.Channels, wc)
+	}
+
+	for _, c := range a.meshChannels {
+		wc := wireMeshAnim{Name: c.name}
+		for _, k := range c.keys {
+			wc.Keys = append(wc.Keys, wireMeshKey{Time: k.time, Value: k.value})
+		}
+		wa.MeshChannels = append(wa.MeshChannels, wc)
+	}
+
+	return wa
+}
+
+func (ws *wireScene) scene() *Scene {
+
+	s := &Scene{
+		Flags:    ws.Flags,
+		Name:     ws.Name,
+		Meshes:   ws.Meshes,
+		Textures: ws.Textures,
+		Metadata: ws.Metadata,
+		Warnings: ws.Warnings,
+	}
+	if s.Metadata == nil {
+		s.Metadata = map[string]Metadata{}
+	}
+
+	nodes := make([]*Node, len(ws.Nodes))
+	for i, wn := range ws.Nodes {
+		n := &Node{
+			Name:           wn.Name,
+			Transformation: wn.Transformation,
+			Children:       []*Node{},
+			MeshIndicies:   wn.MeshIndicies,
+			Metadata:       wn.Metadata,
+		}
+		if n.Metadata == nil {
+			n.Metadata = map[string]Metadata{}
+		}
+
+		// Parents always precede their children.
+		if wn.Parent >= 0 && wn.Parent < i {
+			n.Parent = nodes[wn.Parent]
+			n.Parent.Children = append(n.Parent.Children, n)
+		}
+
+		nodes[i] = n
+	}
+	if len(nodes) > 0 {
+		s.RootNode = nodes[0]
+	}
+
+	for _, wm := range ws.Materials {
+		m := &Material{AllocatedStorage: wm.AllocatedStorage}
+		for _, p := range wm.Properties {
+			m.Properties = append(m.Properties, &MaterialProperty{
+				name:     p.Name,
+				Semantic: p.Semantic,
+				Index:    p.Index,
+				TypeInfo: p.TypeInfo,
+				Data:     p.Data,
+			})
+		}
+		s.Materials = append(s.Materials, m)
+	}
+
+	for _, wa := range ws.Animations {
+		s.Animations = append(s.Animations, wa.animation())
+	}
+
+	for _, l := range ws.Lights {
+		s.Lights = append(s.Lights, &Light{
+			name:                 l.Name,
+			typ:                  l.Type,
+			position:             l.Position,
+			direction:            l.Direction,
+			attenuationConstant:  l.AttenuationConstant,
+			attenuationLinear:    l.AttenuationLinear,
+			attenuationQuadratic: l.AttenuationQuadratic,
+			colorDiffuse:         l.ColorDiffuse,
+			colorSpecular:        l.ColorSpecular,
+			colorAmbient:         l.ColorAmbient,
+			angleInnerCone:       l.AngleInnerCone,
+			angleOuterCone:       l.AngleOuterCone,
+		})
+	}
+
+	for _, c := range ws.Cameras {
+		s.Cameras = append(s.Cameras, &Camera{
+			name:          c.Name,
+			position:      c.Position,
+			up:            c.Up,
+			lookAt:        c.LookAt,
+			horizontalFov: c.HorizontalFov,
+			clipPlaneNear: c.ClipPlaneNear,
+			clipPlaneFar:  c.ClipPlaneFar,
+			aspect:        c.Aspect,
+		})
+	}
+
+	return s
+}
+
+func (wa *wireAnimation) animation() *Animation {
+
+	a := &Animation{
+		name:           wa.Name,
+		duration:       wa.Duration,
+		ticksPerSecond: wa.TicksPerSecond,
+	}
+
+	for _, wc := range wa.Channels {
+		c := &NodeAnim{
+			name:      wc.Name,
+			preState:  wc.PreState,
+			postState: wc.PostState,
+		}
+		for _, k := range wc.PositionKeys {
+			c.positionKeys = append(c.positionKeys, VectorKey{time: k.Time, value: k.Value})
+		}
+		for _, k := range wc.RotationKeys {
+			c.rotationKeys = append(c.rotationKeys, QuatKey{time: k.Time, value: k.Value})
+		}
+		for _, k := range wc.ScalingKeys {
+			c.scalingKeys = append(c.scalingKeys, VectorKey{time: k.Time, value: k.Value})
+		}
+		a.channels = append(a.channels, c)
+	}
+
+	for _, wc := range wa.MeshChannels {
+		c := &MeshAnim{name: wc.Name}
+		for _, k := range wc.Keys {
+			c.keys = append(c.keys, MeshKey{time: k.Time, value: k.Value})
+		}
+		a.meshChannels = append(a.meshChannels, c)
+	}
+
+	return a
+}
@@ -19,53 +19,81 @@ const (
 )
 
 type Light struct {
-	l *C.struct_aiLight
+	name                 string
+	typ                  LightSourceType
+	position             vec3.T
+	direction            vec3.T
+	attenuationConstant  float32
+	attenuationLinear    float32
+	attenuationQuadratic float32
+	colorDiffuse         vec3.T
+	colorSpecular        vec3.T
+	colorAmbient         vec3.T
+	angleInnerCone       float32
+	angleOuterCone       float32
 }
 
 func (l *Light) Name() string {
-	return C.GoStringN(&l.l.mName.data[0], C.int(l.l.mName.length))
+	return l.name
 }
 
 func (l *Light) Type() LightSourceType {
-	return LightSourceType(l.l.mType)
+	return l.typ
 }
 
 func (l *Light) Position() vec3.T {
-	return vec3.T{float32(l.l.mPosition.x), float32(l.l.mPosition.y), float32(l.l.mPosition.z)}
+	return l.position
 }
 
 func (l *Light) Direction() vec3.T {
-	return vec3.T{float32(l.l.mDirection.x), float32(l.l.mDirection.y), float32(l.l.mDirection.z)}
+	return l.direction
 }
 
 func (l *Light) AttenuationConstant() float32 {
-	return float32(l.l.mAttenuationConstant)
+	return l.attenuationConstant
 }
 
 func (l *Light) AttenuationLinear() float32 {
-	return float32(l.l.mAttenuationLinear)
+	return l.attenuationLinear
 }
 
 func (l *Light) AttenuationQuadratic() float32 {
-	return float32(l.l.mAttenuationQuadratic)
+	return l.attenuationQuadratic
 }
 
 func (l *Light) ColorDiffuse() vec3.T {
-	return vec3.T{float32(l.l.mColorDiffuse.r), float32(l.l.mColorDiffuse.g), float32(l.l.mColorDiffuse.b)}
+	return l.colorDiffuse
 }
 
 func (l *Light) ColorSpecular() vec3.T {
-	return vec3.T{float32(l.l.mColorSpecular.r), float32(l.l.mColorSpecular.g), float32(l.l.mColorSpecular.b)}
+	return l.colorSpecular
 }
 
 func (l *Light) ColorAmbient() vec3.T {
-	return vec3.T{float32(l.l.mColorAmbient.r), float32(l.l.mColorAmbient.g), float32(l.l.mColorAmbient.b)}
+	return l.colorAmbient
 }
 
 func (l *Light) AngleInnerCone() float32 {
-	return float32(l.l.mAngleInnerCone)
+	return l.angleInnerCone
 }
 
 func (l *Light) AngleOuterCone() float32 {
-	return float32(l.l.mAngleOuterCone)
+	return l.angleOuterCone
+}
+
+func parseLight(cl *C.struct_aiLight) *Light {
+	return &Light{
+		name:                 parseAiString(cl.mName),
+		typ:                  LightSourceType(cl.mType),
+		position:             parseVec3(&cl.mPosition),
+		direction:            parseVec3(&cl.mDirection),
+		attenuationConstant:  float32(cl.mAttenuationConstant),
+		attenuationLinear:    float32(cl.mAttenuationLinear),
+		attenuationQuadratic: float32(cl.mAttenuationQuadratic),
+		colorDiffuse:         vec3.T{float32(cl.mColorDiffuse.r), float32(cl.mColorDiffuse.g), float32(cl.mColorDiffuse.b)},
+		colorSpecular:        vec3.T{float32(cl.mColorSpecular.r), float32(cl.mColorSpecular.g), float32(cl.mColorSpecular.b)},
+		colorAmbient:         vec3.T{float32(cl.mColorAmbient.r), float32(cl.mColorAmbient.g), float32(cl.mColorAmbient.b)},
+		angleInnerCone:       float32(cl.mAngleInnerCone),
+		angleOuterCone:       float32(cl.mAngleOuterCone),
+	}
 }
@@ -0,0 +1,182 @@
+package assimp
+
+/*
+#cgo linux CFLAGS: -I ./lib/linux
+#cgo darwin,amd64 CFLAGS: -I ./lib/darwin
+#cgo darwin,arm64 CFLAGS: -I ./lib/darwin_arm
+
+#include <stdlib.h>
+#include <assimp/cimport.h>
+
+void goLogCallback(char* message, char* user);
+*/
+import "C"
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Assimp keeps a single logger and error string for the whole process, so
+// imports are serialized. This keeps log output scoped to the import that
+// produced it.
+var importMu sync.Mutex
+
+var (
+	logStreamOnce  sync.Once
+	globalLogger   atomic.Pointer[slog.Logger]
+	globalVerbose  atomic.Bool
+	currentSession atomic.Pointer[logSession]
+)
+
+// logSession collects the log output of a single import.
+type logSession struct {
+	logger    *slog.Logger
+	warnings  []string
+	lastError string
+	// errString is aiGetErrorString, read under the import lock if the
+	// import failed.
+	errString string
+}
+
+// SetLogger routes Assimp's log output to l for imports that don't set
+// ImportOptions.Logger. A nil logger discards the output.
+func SetLogger(l *slog.Logger) {
+	globalLogger.Store(l)
+}
+
+// SetVerboseLogging enables Assimp's verbose logging, which adds debug output
+// and import statistics at a noticeable cost in import time.
+func SetVerboseLogging(enabled bool) {
+
+	importMu.Lock()
+	defer importMu.Unlock()
+
+	globalVerbose.Store(enabled)
+	setAiVerbose(enabled)
+}
+
+func setAiVerbose(enabled bool) {
+	if enabled {
+		C.aiEnableVerboseLogging(C.AI_TRUE)
+	} else {
+		C.aiEnableVerboseLogging(C.AI_FALSE)
+	}
+}
+
+// attachLogStream registers goLogCallback with Assimp. The stream stays
+// attached for the lifetime of the process.
+func attachLogStream() {
+	logStreamOnce.Do(func() {
+		stream := (*C.struct_aiLogStream)(C.calloc(1, C.sizeof_struct_aiLogStream))
+		stream.callback = C.aiLogStreamCallback(C.goLogCallback)
+		C.aiAttachLogStream(stream)
+		setAiVerbose(globalVerbose.Load())
+	})
+}
+
+// importLocked runs importFn while holding the import lock. The returned
+// session holds the warnings Assimp logged meanwhile and, if no scene was
+// produced, the error Assimp reported for this import.
+func importLocked(opts *ImportOptions, importFn func() *C.struct_aiScene) (cs *C.struct_aiScene, session *logSession) {
+
+	attachLogStream()
+
+	var errString string
+
+	session = withLogSession(opts, func() {
+		cs = importFn()
+		if cs == nil {
+			errString = C.GoString(C.aiGetErrorString())
+		}
+	})
+
+	session.errString = errString
+	return cs, session
+}
+
+// withLogSession runs fn under the import lock with log output routed to the
+// logger configured in opts, and returns the session that recorded it.
+func withLogSession(opts *ImportOptions, fn func()) *logSession {
+
+	importMu.Lock()
+	defer importMu.Unlock()
+
+	session := &logSession{logger: globalLogger.Load()}
+
+	if opts != nil {
+		if opts.Logger != nil {
+			session.logger = opts.Logger
+		}
+
+		if opts.VerboseLogging && !globalVerbose.Load() {
+			setAiVerbose(true)
+			defer setAiVerbose(false)
+		}
+	}
+
+	currentSession.Store(session)
+	defer currentSession.Store(nil)
+
+	fn()
+
+	return session
+}
+
+var logPrefix = regexp.MustCompile(`^(Debug|Info|Warn|Error),\s*T\d+:\s?`)
+
+// parseLogMessage splits an Assimp log line into its severity and message.
+func parseLogMessage(line string) (slog.Level, string) {
+
+	line = strings.TrimRight(line, "\r\n")
+
+	m := logPrefix.FindStringSubmatch(line)
+	if m == nil {
+		return slog.LevelInfo, line
+	}
+
+	msg := line[len(m[0]):]
+
+	switch m[1] {
+	case "Debug":
+		return slog.LevelDebug, msg
+	case "Warn":
+		return slog.LevelWarn, msg
+	case "Error":
+		return slog.LevelError, msg
+	default:
+		return slog.LevelInfo, msg
+	}
+}
+
+//export goLogCallback
+func goLogCallback(cmsg *C.char, user *C.char) {
+	handleLogLine(C.GoString(cmsg))
+}
+
+// handleLogLine forwards a log line to the logger of the running import, or
+// to the global logger when no import is running.
+func handleLogLine(line string) {
+
+	level, msg := parseLogMessage(line)
+
+	logger := globalLogger.Load()
+
+	if session := currentSession.Load(); session != nil {
+		logger = session.logger
+
+		switch level {
+		case slog.LevelWarn:
+			session.warnings = append(session.warnings, msg)
+		case slog.LevelError:
+			session.lastError = msg
+		}
+	}
+
+	if logger != nil {
+		logger.Log(context.Background(), level, msg, slog.String("source", "assimp"))
+	}
+}
@@ -0,0 +1,70 @@
+package assimp
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestParseLogMessage 测试Assimp日志前缀的解析
+func TestParseLogMessage(t *testing.T) {
+	tests := []struct {
+		line  string
+		level slog.Level
+		msg   string
+	}{
+		{"Debug, T0: Load model.obj\n", slog.LevelDebug, "Load model.obj"},
+		{"Info,  T0: Found a matching importer for this file format: Wavefront Object Importer.\n", slog.LevelInfo, "Found a matching importer for this file format: Wavefront Object Importer."},
+		{"Warn,  T12: OBJ: unexpected line\n", slog.LevelWarn, "OBJ: unexpected line"},
+		{"Error, T3: Failed to open file model.mtl.\n", slog.LevelError, "Failed to open file model.mtl."},
+		{"Skipping one or more lines with the same contents\n", slog.LevelInfo, "Skipping one or more lines with the same contents"},
+	}
+
+	for _, test := range tests {
+		level, msg := parseLogMessage(test.line)
+		if level != test.level || msg != test.msg {
+			t.Errorf("parseLogMessage(%q) = %v, %q; expected %v, %q", test.line, level, msg, test.level, test.msg)
+		}
+	}
+}
+
+// TestImportLockedLogging 测试导入期间日志路由到导入专用的logger并收集警告
+func TestImportLockedLogging(t *testing.T) {
+	var global, scoped bytes.Buffer
+
+	SetLogger(slog.New(slog.NewTextHandler(&global, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	opts := &ImportOptions{
+		Logger: slog.New(slog.NewTextHandler(&scoped, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	session := withLogSession(opts, func() {
+		handleLogLine("Info,  T0: Load model.obj\n")
+		handleLogLine("Warn,  T0: OBJ: unexpected line\n")
+		handleLogLine("Error, T0: Failed to open file model.mtl.\n")
+	})
+
+	if len(session.warnings) != 1 || session.warnings[0] != "OBJ: unexpected line" {
+		t.Errorf("Expected one collected warning, got %v", session.warnings)
+	}
+
+	if session.lastError != "Failed to open file model.mtl." {
+		t.Errorf("Expected last error to be recorded, got %q", session.lastError)
+	}
+
+	if !strings.Contains(scoped.String(), "Load model.obj") {
+		t.Errorf("Expected scoped logger to receive import output, got %q", scoped.String())
+	}
+
+	if global.Len() != 0 {
+		t.Errorf("Expected global logger to receive nothing during the import, got %q", global.String())
+	}
+
+	// 导入之外的日志进入全局logger
+	handleLogLine("Info,  T0: outside\n")
+	if !strings.Contains(global.String(), "outside") {
+		t.Errorf("Expected global logger to receive output outside imports, got %q", global.String())
+	}
+}
@@ -1,34 +1,18 @@
 package assimp
 
-/*
-#cgo CFLAGS: -I ./lib
-#include <stdlib.h> //Needed for C.free
-
-#include <assimp/scene.h>
-
-//Functions
-unsigned int aiGetMaterialTextureCount(const struct aiMaterial* pMat, enum aiTextureType type);
-
-enum aiReturn aiGetMaterialTexture(
-	const struct aiMaterial* mat,
-    enum aiTextureType type,
-    unsigned int  index,
-    struct aiString* path,
-    enum aiTextureMapping* mapping,
-    unsigned int* uvindex,
-    ai_real* blend,
-    enum aiTextureOp* op,
-    enum aiTextureMapMode* mapmode,
-    unsigned int* flags);
-*/
-import "C"
 import (
+	"encoding/binary"
 	"errors"
-	"fmt"
+)
+
+// Material property keys used by the helpers below, see assimp/material.h.
+const (
+	matKeyName        = "?mat.name"
+	matKeyTextureBase = "$tex.file"
+	matKeyUVWSource   = "$tex.uvwsrc"
 )
 
 type Material struct {
-	cMat             *C.struct_aiMaterial
 	Properties       []*MaterialProperty
 	AllocatedStorage uint
 }
@@ -41,32 +25,83 @@ type MaterialProperty struct {
 	Data     []byte
 }
 
+// findProperty returns the property with the given key, semantic and index.
+func (m *Material) findProperty(key string, semantic TextureType, index uint) *MaterialProperty {
+	for _, p := range m.Properties {
+		if p.name == key && p.Semantic == semantic && p.Index == index {
+			return p
+		}
+	}
+	return nil
+}
+
+// stringValue decodes a string property. Assimp stores them as a 32 bit
+// length followed by the characters and a terminating zero.
+func (mp *MaterialProperty) stringValue() (string, bool) {
+	if mp.TypeInfo != MatPropTypeInfoString || len(mp.Data) < 4 {
+		return "", false
+	}
+
+	n := int(binary.NativeEndian.Uint32(mp.Data))
+	if n > len(mp.Data)-4 {
+		return "", false
+	}
+
+	return string(mp.Data[4 : 4+n]), true
+}
+
+// intValue decodes an int32 property.
+func (mp *MaterialProperty) intValue() (int32, bool) {
+	if mp.TypeInfo != MatPropTypeInfoInt32 || len(mp.Data) < 4 {
+		return 0, false
+	}
+
+	return int32(binary.NativeEndian.Uint32(mp.Data)), true
+}
+
+// GetMaterialTextureCount returns the number of textures of texType, like
+// aiGetMaterialTextureCount.
 func GetMaterialTextureCount(m *Material, texType TextureType) int {
-	return int(C.aiGetMaterialTextureCount(m.cMat, uint32(texType)))
+	count := 0
+	for _, p := range m.Properties {
+		if p.name == matKeyTextureBase && p.Semantic == texType && int(p.Index) >= count {
+			count = int(p.Index) + 1
+		}
+	}
+	return count
 }
 
 type GetMatTexInfo struct {
 	Path string
+	// UVIndex is the texture coordinate channel of the mesh the texture is
+	// mapped with, from $tex.uvwsrc. It defaults to 0.
+	UVIndex uint
 }
 
+// GetMaterialTexture returns the texture of texType at texIndex, like
+// aiGetMaterialTexture.
 func GetMaterialTexture(m *Material, texType TextureType, texIndex uint) (*GetMatTexInfo, error) {
-	outCPath := &C.struct_aiString{}
-	status := aiReturn(C.aiGetMaterialTexture(m.cMat, uint32(texType), C.uint(texIndex), outCPath, nil, nil, nil, nil, nil, nil))
-	if status == aiReturnSuccess {
-		return &GetMatTexInfo{
-			Path: parseAiString(*outCPath),
-		}, nil
+	p := m.findProperty(matKeyTextureBase, texType, texIndex)
+	if p == nil {
+		return nil, errors.New("get texture failed: no texture of that type and index")
+	}
+
+	path, ok := p.stringValue()
+	if !ok {
+		return nil, errors.New("get texture failed: texture path is not a string")
 	}
 
-	if status == aiReturnFailure {
-		return nil, errors.New("get texture failed: " + getAiErr().Error())
+	info := &GetMatTexInfo{
+		Path: path,
 	}
 
-	if status == aiReturnOutofMemory {
-		return nil, errors.New("get texture failed: out of memory")
+	if p := m.findProperty(matKeyUVWSource, texType, texIndex); p != nil {
+		if v, ok := p.intValue(); ok && v >= 0 {
+			info.UVIndex = uint(v)
+		}
 	}
 
-	return nil, errors.New("get texture failed: unknown error with code " + fmt.Sprintf("%v", status))
+	return info, nil
 }
 
 // Helper to convert ASSIMP property names to better format
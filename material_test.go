@@ -1,7 +1,10 @@
 package assimp
 
 import (
+	"encoding/binary"
 	"testing"
+
+	"github.com/flywave/go3d/vec3"
 )
 
 // TestMaterialCreation 测试材质创建
@@ -272,3 +275,114 @@ func TestMaterialPropertyDataSize(t *testing.T) {
 		}
 	}
 }
+
+// aiStringProperty 按Assimp的格式编码字符串属性: 32位长度 + 字符 + 结尾的0
+func aiStringProperty(name string, semantic TextureType, index uint, value string) *MaterialProperty {
+	data := binary.NativeEndian.AppendUint32(nil, uint32(len(value)))
+	data = append(data, value...)
+	data = append(data, 0)
+
+	return &MaterialProperty{
+		name:     name,
+		Semantic: semantic,
+		Index:    index,
+		TypeInfo: MatPropTypeInfoString,
+		Data:     data,
+	}
+}
+
+// aiIntProperty 按Assimp的格式编码int32属性
+func aiIntProperty(name string, semantic TextureType, index uint, value int32) *MaterialProperty {
+	return &MaterialProperty{
+		name:     name,
+		Semantic: semantic,
+		Index:    index,
+		TypeInfo: MatPropTypeInfoInt32,
+		Data:     binary.NativeEndian.AppendUint32(nil, uint32(value)),
+	}
+}
+
+// TestGetMaterialTexture 测试不依赖C数据的纹理查询
+func TestGetMaterialTexture(t *testing.T) {
+	material := &Material{
+		Properties: []*MaterialProperty{
+			aiStringProperty("?mat.name", TextureTypeNone, 0, "wood"),
+			aiStringProperty("$tex.file", TextureTypeDiffuse, 0, "textures/wood.png"),
+			aiStringProperty("$tex.file", TextureTypeDiffuse, 1, "textures/wood_detail.png"),
+			aiStringProperty("$tex.file", TextureTypeNormal, 0, "textures/wood_n.png"),
+		},
+	}
+
+	if count := GetMaterialTextureCount(material, TextureTypeDiffuse); count != 2 {
+		t.Errorf("Expected 2 diffuse textures, got %d", count)
+	}
+
+	if count := GetMaterialTextureCount(material, TextureTypeSpecular); count != 0 {
+		t.Errorf("Expected 0 specular textures, got %d", count)
+	}
+
+	info, err := GetMaterialTexture(material, TextureTypeDiffuse, 1)
+	if err != nil {
+		t.Fatalf("Expected texture, got %v", err)
+	}
+
+	if info.Path != "textures/wood_detail.png" {
+		t.Errorf("Expected path 'textures/wood_detail.png', got '%s'", info.Path)
+	}
+
+	if _, err := GetMaterialTexture(material, TextureTypeNormal, 1); err == nil {
+		t.Error("Expected error for missing texture index")
+	}
+}
+
+// TestMaterialStringValue 测试字符串属性的解码
+func TestMaterialStringValue(t *testing.T) {
+	prop := aiStringProperty("?mat.name", TextureTypeNone, 0, "steel")
+
+	if v, ok := prop.stringValue(); !ok || v != "steel" {
+		t.Errorf("Expected 'steel', got %q (%v)", v, ok)
+	}
+
+	truncated := &MaterialProperty{TypeInfo: MatPropTypeInfoString, Data: []byte{0xff, 0, 0, 0, 'a'}}
+	if _, ok := truncated.stringValue(); ok {
+		t.Error("Expected truncated string property to be rejected")
+	}
+
+	float := &MaterialProperty{TypeInfo: MatPropTypeInfoFloat32, Data: []byte{0, 0, 0x80, 0x3f}}
+	if _, ok := float.stringValue(); ok {
+		t.Error("Expected non-string property to be rejected")
+	}
+}
+
+// TestGetMaterialTextureUVIndex 测试纹理的UV通道来自$tex.uvwsrc
+func TestGetMaterialTextureUVIndex(t *testing.T) {
+	material := &Material{
+		Properties: []*MaterialProperty{
+			aiStringProperty("$tex.file", TextureTypeDiffuse, 0, "albedo.png"),
+			aiStringProperty("$tex.file", TextureTypeLightmap, 0, "lightmap.png"),
+			aiIntProperty("$tex.uvwsrc", TextureTypeLightmap, 0, 1),
+		},
+	}
+
+	if info, err := GetMaterialTexture(material, TextureTypeDiffuse, 0); err != nil || info.UVIndex != 0 {
+		t.Errorf("Expected UV channel 0 by default, got %v, %v", info, err)
+	}
+	if info, err := GetMaterialTexture(material, TextureTypeLightmap, 0); err != nil || info.UVIndex != 1 {
+		t.Errorf("Expected UV channel 1 for the lightmap, got %v, %v", info, err)
+	}
+
+	mesh := &Mesh{}
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}}
+
+	if _, ok := mesh.TextureUVChannel(material, TextureTypeLightmap, 0); ok {
+		t.Error("Expected no channel when the mesh lacks the lightmap UVs")
+	}
+
+	mesh.TexCoords[1] = []vec3.T{{0.5, 0.5, 0}}
+	if ch, ok := mesh.TextureUVChannel(material, TextureTypeLightmap, 0); !ok || ch != 1 {
+		t.Errorf("Expected lightmap channel 1, got %d, %v", ch, ok)
+	}
+	if ch, ok := mesh.TextureUVChannel(material, TextureTypeDiffuse, 0); !ok || ch != 0 {
+		t.Errorf("Expected diffuse channel 0, got %d, %v", ch, ok)
+	}
+}
@@ -1,7 +1,10 @@
 package assimp
 
 import (
+	"slices"
+
 	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec2"
 	"github.com/flywave/go3d/vec3"
 	"github.com/flywave/go3d/vec4"
 )
@@ -22,8 +25,17 @@ type Mesh struct {
 
 	TexCoords            [MaxTexCoords][]vec3.T
 	TexCoordChannelCount [MaxTexCoords]uint
+	// TexCoordNames holds the names of the texture coordinate channels,
+	// e.g. "UVMap" or "Lightmap", if the format has them.
+	TexCoordNames [MaxTexCoords]string
+
+	Faces []Face
+	// Indices and FaceOffsets hold the faces of a zero-copy mesh as a flat
+	// index buffer: face i is Indices[FaceOffsets[i]:FaceOffsets[i+1]].
+	// Faces is left empty for such meshes, see CopyFaces.
+	Indices     []uint32
+	FaceOffsets []uint32
 
-	Faces       []Face
 	Bones       []*Bone
 	AnimMeshes  []*AnimMesh
 	AABB        AABB
@@ -31,6 +43,220 @@ type Mesh struct {
 
 	MaterialIndex uint
 	Name          string
+
+	// owner is set for zero-copy meshes and keeps the scene, and with it
+	// the imported data the views point into, reachable.
+	owner *Scene
+}
+
+// IsView reports whether the vertex data of the mesh points straight into
+// the imported C data, see ImportOptions.ZeroCopyMeshes.
+func (m *Mesh) IsView() bool {
+	return m != nil && m.owner != nil
+}
+
+// Detach copies the vertex data of a zero-copy mesh into Go memory, so it
+// stays valid after the scene is closed. It does nothing for other meshes.
+func (m *Mesh) Detach() {
+
+	if m == nil || m.owner == nil {
+		return
+	}
+
+	m.Vertices = slices.Clone(m.Vertices)
+	m.Normals = slices.Clone(m.Normals)
+	m.Tangents = slices.Clone(m.Tangents)
+	m.BitTangents = slices.Clone(m.BitTangents)
+
+	for i := range m.ColorSets {
+		m.ColorSets[i] = slices.Clone(m.ColorSets[i])
+	}
+
+	for i := range m.TexCoords {
+		m.TexCoords[i] = slices.Clone(m.TexCoords[i])
+	}
+
+	m.owner = nil
+}
+
+// dropViews clears the vertex data of a zero-copy mesh before the C data it
+// points into is released.
+func (m *Mesh) dropViews() {
+
+	if m == nil || m.owner == nil {
+		return
+	}
+
+	m.Vertices = nil
+	m.Normals = nil
+	m.Tangents = nil
+	m.BitTangents = nil
+	m.ColorSets = [MaxColorSets][]vec4.T{}
+	m.TexCoords = [MaxTexCoords][]vec3.T{}
+
+	m.owner = nil
+}
+
+// CopyFaces returns the faces of the mesh as a []Face, building them from the
+// flat index buffer for zero-copy meshes.
+func (m *Mesh) CopyFaces() []Face {
+
+	if len(m.FaceOffsets) == 0 {
+		faces := make([]Face, len(m.Faces))
+		for i, f := range m.Faces {
+			faces[i].Indices = slices.Clone(f.Indices)
+		}
+		return faces
+	}
+
+	faces := make([]Face, len(m.FaceOffsets)-1)
+	for i := range faces {
+
+		idx := m.Indices[m.FaceOffsets[i]:m.FaceOffsets[i+1]]
+
+		faces[i].Indices = make([]uint, len(idx))
+		for j, v := range idx {
+			faces[i].Indices[j] = uint(v)
+		}
+	}
+
+	return faces
+}
+
+// TexCoords2D returns a copy of the texture coordinate channel as 2D
+// coordinates. It returns nil if the channel is empty or doesn't have 2
+// components, see TexCoordChannelCount.
+func (m *Mesh) TexCoords2D(channel int) []vec2.T {
+
+	if channel < 0 || channel >= MaxTexCoords || m.TexCoordChannelCount[channel] != 2 || len(m.TexCoords[channel]) == 0 {
+		return nil
+	}
+
+	uvs := make([]vec2.T, len(m.TexCoords[channel]))
+	for i, uv := range m.TexCoords[channel] {
+		uvs[i] = vec2.T{uv[0], uv[1]}
+	}
+
+	return uvs
+}
+
+// TexCoordChannel returns the index of the channel named name, or -1.
+func (m *Mesh) TexCoordChannel(name string) int {
+	for i, n := range m.TexCoordNames {
+		if n == name && len(m.TexCoords[i]) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// TextureUVChannel returns the texture coordinate channel of the mesh used
+// by the texture of texType at texIndex in mat, see GetMatTexInfo.UVIndex.
+// ok is false if the mesh doesn't have that channel.
+func (m *Mesh) TextureUVChannel(mat *Material, texType TextureType, texIndex uint) (channel int, ok bool) {
+
+	info, err := GetMaterialTexture(mat, texType, texIndex)
+	if err != nil {
+		return -1, false
+	}
+
+	if info.UVIndex >= MaxTexCoords || len(m.TexCoords[info.UVIndex]) == 0 {
+		return -1, false
+	}
+
+	return int(info.UVIndex), true
+}
+
+// eachFace calls fn with the indices of every face, in both the []Face and
+// the flat index buffer layout. The slice is only valid during the call.
+func (m *Mesh) eachFace(fn func(idx []uint32)) {
+
+	if len(m.FaceOffsets) > 0 {
+		for i := 0; i+1 < len(m.FaceOffsets); i++ {
+			fn(m.Indices[m.FaceOffsets[i]:m.FaceOffsets[i+1]])
+		}
+		return
+	}
+
+	var buf []uint32
+	for _, f := range m.Faces {
+		buf = buf[:0]
+		for _, idx := range f.Indices {
+			buf = append(buf, uint32(idx))
+		}
+		fn(buf)
+	}
+}
+
+// primitiveTypes returns PrimitiveTypes, or the types of the faces if it
+// isn't set, e.g. for meshes built in Go.
+func (m *Mesh) primitiveTypes() PrimitiveType {
+
+	if m.PrimitiveTypes != 0 {
+		return m.PrimitiveTypes
+	}
+
+	var pt PrimitiveType
+	m.eachFace(func(idx []uint32) {
+		switch len(idx) {
+		case 0:
+		case 1:
+			pt |= PrimitiveTypePoint
+		case 2:
+			pt |= PrimitiveTypeLine
+		case 3:
+			pt |= PrimitiveTypeTriangle
+		default:
+			pt |= PrimitiveTypePolygon
+		}
+	})
+
+	return pt
+}
+
+// IsTriangleOnly reports whether all faces of the mesh are triangles.
+func (m *Mesh) IsTriangleOnly() bool {
+	return m.primitiveTypes()&^PrimitiveTypeNGONEncodingFlag == PrimitiveTypeTriangle
+}
+
+// Triangles returns the triangles of the mesh. Polygons are split into
+// triangle fans, points and lines are left out.
+func (m *Mesh) Triangles() [][3]uint32 {
+
+	var tris [][3]uint32
+	m.eachFace(func(idx []uint32) {
+		for i := 1; i+1 < len(idx); i++ {
+			tris = append(tris, [3]uint32{idx[0], idx[i], idx[i+1]})
+		}
+	})
+
+	return tris
+}
+
+// Lines returns the line faces of the mesh.
+func (m *Mesh) Lines() [][2]uint32 {
+
+	var lines [][2]uint32
+	m.eachFace(func(idx []uint32) {
+		if len(idx) == 2 {
+			lines = append(lines, [2]uint32{idx[0], idx[1]})
+		}
+	})
+
+	return lines
+}
+
+// Points returns the vertex indices of the point faces of the mesh.
+func (m *Mesh) Points() []uint32 {
+
+	var points []uint32
+	m.eachFace(func(idx []uint32) {
+		if len(idx) == 1 {
+			points = append(points, idx[0])
+		}
+	})
+
+	return points
 }
 
 type Face struct {
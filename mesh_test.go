@@ -1,9 +1,14 @@
 package assimp
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec2"
 	"github.com/flywave/go3d/vec3"
 	"github.com/flywave/go3d/vec4"
 )
@@ -375,4 +380,231 @@ func TestMeshValidation(t *testing.T) {
 	if len(meshInvalid.Faces) != 1 {
 		t.Errorf("Expected 1 face, got %d", len(meshInvalid.Faces))
 	}
-}
\ No newline at end of file
+}
+
+// TestMeshCopyFaces 测试从扁平索引缓冲和Faces复制面
+func TestMeshCopyFaces(t *testing.T) {
+	flat := &Mesh{
+		Indices:     []uint32{0, 1, 2, 2, 3, 0, 4},
+		FaceOffsets: []uint32{0, 3, 6, 7},
+	}
+
+	faces := flat.CopyFaces()
+	if len(faces) != 3 {
+		t.Fatalf("Expected 3 faces, got %d", len(faces))
+	}
+	if len(faces[1].Indices) != 3 || faces[1].Indices[0] != 2 || faces[1].Indices[2] != 0 {
+		t.Errorf("Unexpected second face %v", faces[1].Indices)
+	}
+	if len(faces[2].Indices) != 1 || faces[2].Indices[0] != 4 {
+		t.Errorf("Expected point face, got %v", faces[2].Indices)
+	}
+
+	eager := &Mesh{Faces: []Face{{Indices: []uint{0, 1, 2}}}}
+	copied := eager.CopyFaces()
+	copied[0].Indices[0] = 9
+	if eager.Faces[0].Indices[0] != 0 {
+		t.Error("Expected CopyFaces to copy the indices")
+	}
+}
+
+// TestMeshViewDetach 测试零拷贝网格在Detach后拥有独立的数据
+func TestMeshViewDetach(t *testing.T) {
+	backing := []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	colors := []vec4.T{{1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}}
+
+	scene := &Scene{}
+	mesh := &Mesh{Vertices: backing, owner: scene}
+	mesh.ColorSets[0] = colors
+	mesh.TexCoords[0] = backing
+	scene.Meshes = []*Mesh{mesh}
+
+	if !mesh.IsView() {
+		t.Fatal("Expected mesh to be a view")
+	}
+
+	scene.Detach()
+
+	if mesh.IsView() {
+		t.Error("Expected mesh to own its data after Detach")
+	}
+
+	backing[1] = vec3.T{9, 9, 9}
+	colors[0] = vec4.T{}
+	if mesh.Vertices[1] != (vec3.T{1, 0, 0}) || mesh.TexCoords[0][1] != (vec3.T{1, 0, 0}) {
+		t.Error("Expected Detach to copy the vertex data")
+	}
+	if mesh.ColorSets[0][0] != (vec4.T{1, 0, 0, 1}) {
+		t.Error("Expected Detach to copy the colors")
+	}
+}
+
+// TestMeshViewClose 测试关闭场景会清除零拷贝网格的数据
+func TestMeshViewClose(t *testing.T) {
+	scene := &Scene{}
+	view := &Mesh{
+		Vertices:    []vec3.T{{0, 0, 0}},
+		Indices:     []uint32{0},
+		FaceOffsets: []uint32{0, 1},
+		owner:       scene,
+	}
+	owned := &Mesh{Vertices: []vec3.T{{1, 1, 1}}}
+	scene.Meshes = []*Mesh{view, owned}
+
+	scene.Close()
+
+	if view.Vertices != nil || view.IsView() {
+		t.Error("Expected Close to clear the views")
+	}
+	if len(view.Indices) != 1 {
+		t.Error("Expected the flat index buffer to survive Close")
+	}
+	if len(owned.Vertices) != 1 {
+		t.Error("Expected meshes owning their data to survive Close")
+	}
+}
+
+// TestMeshTexCoords2D 测试二维纹理坐标和通道名称
+func TestMeshTexCoords2D(t *testing.T) {
+	mesh := &Mesh{
+		TexCoords:            [MaxTexCoords][]vec3.T{{{0.25, 0.75, 0}, {1, 0, 0}}, {{0, 0, 1}}},
+		TexCoordChannelCount: [MaxTexCoords]uint{2, 3},
+		TexCoordNames:        [MaxTexCoords]string{"UVMap", "Volume"},
+	}
+
+	uvs := mesh.TexCoords2D(0)
+	if len(uvs) != 2 || uvs[0] != (vec2.T{0.25, 0.75}) {
+		t.Errorf("Expected 2D coordinates of channel 0, got %v", uvs)
+	}
+	uvs[0][0] = 9
+	if mesh.TexCoords[0][0][0] != 0.25 {
+		t.Error("Expected TexCoords2D to return a copy")
+	}
+
+	if mesh.TexCoords2D(1) != nil {
+		t.Error("Expected nil for a 3D channel")
+	}
+	if mesh.TexCoords2D(2) != nil || mesh.TexCoords2D(-1) != nil || mesh.TexCoords2D(MaxTexCoords) != nil {
+		t.Error("Expected nil for empty or invalid channels")
+	}
+
+	if ch := mesh.TexCoordChannel("Volume"); ch != 1 {
+		t.Errorf("Expected channel 1 for Volume, got %d", ch)
+	}
+	if ch := mesh.TexCoordChannel("Lightmap"); ch != -1 {
+		t.Errorf("Expected -1 for a missing channel, got %d", ch)
+	}
+}
+
+// TestMeshPrimitives 测试按图元类型读取面的索引
+func TestMeshPrimitives(t *testing.T) {
+	faces := []Face{
+		{Indices: []uint{0, 1, 2}},
+		{Indices: []uint{0, 1, 2, 3}},
+		{Indices: []uint{1, 3}},
+		{Indices: []uint{2}},
+	}
+	mesh := &Mesh{Faces: faces}
+	flat := &Mesh{
+		Indices:     []uint32{0, 1, 2, 0, 1, 2, 3, 1, 3, 2},
+		FaceOffsets: []uint32{0, 3, 7, 9, 10},
+	}
+
+	for name, m := range map[string]*Mesh{"faces": mesh, "flat": flat} {
+		tris := m.Triangles()
+		if len(tris) != 3 || tris[1] != [3]uint32{0, 1, 2} || tris[2] != [3]uint32{0, 2, 3} {
+			t.Errorf("%s: expected the triangle and a fanned quad, got %v", name, tris)
+		}
+		if lines := m.Lines(); len(lines) != 1 || lines[0] != [2]uint32{1, 3} {
+			t.Errorf("%s: expected one line, got %v", name, lines)
+		}
+		if points := m.Points(); len(points) != 1 || points[0] != 2 {
+			t.Errorf("%s: expected one point, got %v", name, points)
+		}
+		if m.IsTriangleOnly() {
+			t.Errorf("%s: expected mixed primitives not to be triangle only", name)
+		}
+	}
+
+	if !(&Mesh{Faces: faces[:1]}).IsTriangleOnly() {
+		t.Error("Expected a single triangle to be triangle only")
+	}
+
+	// 已设置的PrimitiveTypes优先于面的推断
+	tagged := &Mesh{PrimitiveTypes: PrimitiveTypeTriangle | PrimitiveTypeNGONEncodingFlag, Faces: faces[:1]}
+	if !tagged.IsTriangleOnly() {
+		t.Error("Expected the NGON encoding flag to be ignored")
+	}
+	if (&Mesh{PrimitiveTypes: PrimitiveTypeLine}).IsTriangleOnly() {
+		t.Error("Expected a line mesh not to be triangle only")
+	}
+}
+
+// TestPrimitiveTypeString 测试图元类型的字符串表示
+func TestPrimitiveTypeString(t *testing.T) {
+	tests := []struct {
+		pt   PrimitiveType
+		want string
+	}{
+		{0, "None"},
+		{PrimitiveTypeTriangle, "Triangle"},
+		{PrimitiveTypePoint | PrimitiveTypeLine, "Point|Line"},
+		{PrimitiveTypePolygon | 0x40, "Polygon|0x40"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pt.String(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+// writeGridOBJ 写入一个由n*n个四边形组成的OBJ文件
+func writeGridOBJ(b *testing.B, n int) string {
+	b.Helper()
+
+	var buf bytes.Buffer
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			fmt.Fprintf(&buf, "v %d %d 0\nvt %g %g\n", x, y, float32(x)/float32(n), float32(y)/float32(n))
+		}
+	}
+	buf.WriteString("vn 0 0 1\n")
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i := y*(n+1) + x + 1
+			fmt.Fprintf(&buf, "f %d/%d/1 %d/%d/1 %d/%d/1 %d/%d/1\n", i, i, i+1, i+1, i+n+2, i+n+2, i+n+1, i+n+1)
+		}
+	}
+
+	path := filepath.Join(b.TempDir(), "grid.obj")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+func benchmarkImportGrid(b *testing.B, opts *ImportOptions) {
+	path := writeGridOBJ(b, 300)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scene, release, err := ImportFileWithOptions(path, PostProcessTriangulate|PostProcessJoinIdenticalVertices, opts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(scene.Meshes) == 0 {
+			b.Fatal("Expected a mesh")
+		}
+		release()
+	}
+}
+
+func BenchmarkImportMeshesEager(b *testing.B) {
+	benchmarkImportGrid(b, &ImportOptions{})
+}
+
+func BenchmarkImportMeshesZeroCopy(b *testing.B) {
+	benchmarkImportGrid(b, &ImportOptions{ZeroCopyMeshes: true})
+}
@@ -0,0 +1,86 @@
+package assimp
+
+import "strings"
+
+// MetaNumber is the set of numeric types GetMetaNumber converts to. It is
+// wider than the stored types so values can be read as e.g. int64 or uint32.
+type MetaNumber interface {
+	~int32 | ~int64 | ~uint32 | ~uint64 | ~float32 | ~float64 | ~int | ~uint
+}
+
+// GetMeta returns the value for key if it has type T. Keys of nested
+// metadata are separated by "/", e.g. "Pset_WallCommon/FireRating".
+func GetMeta[T any](m map[string]Metadata, key string) (T, bool) {
+
+	var zero T
+
+	entry, ok := lookupMeta(m, key)
+	if !ok {
+		return zero, false
+	}
+
+	v, ok := entry.Value.(T)
+	return v, ok
+}
+
+// GetMetaNumber returns the numeric value for key converted to T, whatever
+// numeric type it is stored as. Keys are looked up like GetMeta.
+func GetMetaNumber[T MetaNumber](m map[string]Metadata, key string) (T, bool) {
+
+	entry, ok := lookupMeta(m, key)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := entry.Value.(type) {
+	case int32:
+		return T(v), true
+	case uint64:
+		return T(v), true
+	case float32:
+		return T(v), true
+	case float64:
+		return T(v), true
+	default:
+		return 0, false
+	}
+}
+
+// lookupMeta returns the entry for a "/" separated key. Keys that contain a
+// "/" themselves are matched first.
+func lookupMeta(m map[string]Metadata, key string) (Metadata, bool) {
+
+	if entry, ok := m[key]; ok {
+		return entry, true
+	}
+
+	head, rest, found := strings.Cut(key, "/")
+	if !found {
+		return Metadata{}, false
+	}
+
+	nested, ok := m[head].Value.(map[string]Metadata)
+	if !ok {
+		return Metadata{}, false
+	}
+
+	return lookupMeta(nested, rest)
+}
+
+// cloneMetadata returns a deep copy of m, nested metadata maps included.
+func cloneMetadata(m map[string]Metadata) map[string]Metadata {
+
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[string]Metadata, len(m))
+	for k, v := range m {
+		if nested, ok := v.Value.(map[string]Metadata); ok {
+			v.Value = cloneMetadata(nested)
+		}
+		c[k] = v
+	}
+
+	return c
+}
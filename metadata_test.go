@@ -0,0 +1,131 @@
+package assimp
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildTestMetadata 构建包含嵌套属性集的元数据
+func buildTestMetadata() map[string]Metadata {
+	return map[string]Metadata{
+		"SourceAsset_Format": {Type: MetadataTypeString, Value: "Industry Foundation Classes"},
+		"UnitScaleFactor":    {Type: MetadataTypeFloat32, Value: float32(2.54)},
+		"FileSize":           {Type: MetadataTypeUint64, Value: uint64(1 << 40)},
+		"Revision":           {Type: MetadataTypeInt32, Value: int32(7)},
+		"Offset":             {Type: MetadataTypeVec3, Value: vec3.T{1, 2, 3}},
+		"a/b":                {Type: MetadataTypeBool, Value: true},
+		"Pset_WallCommon": {Type: MetadataTypeMetadata, Value: map[string]Metadata{
+			"FireRating":  {Type: MetadataTypeString, Value: "REI60"},
+			"LoadBearing": {Type: MetadataTypeBool, Value: true},
+			"Thermal": {Type: MetadataTypeMetadata, Value: map[string]Metadata{
+				"UValue": {Type: MetadataTypeFloat64, Value: 0.24},
+			}},
+		}},
+	}
+}
+
+// TestGetMeta 测试按类型读取元数据
+func TestGetMeta(t *testing.T) {
+	meta := buildTestMetadata()
+
+	if v, ok := GetMeta[string](meta, "SourceAsset_Format"); !ok || v != "Industry Foundation Classes" {
+		t.Errorf("Expected source format, got %q, %v", v, ok)
+	}
+	if v, ok := GetMeta[vec3.T](meta, "Offset"); !ok || v != (vec3.T{1, 2, 3}) {
+		t.Errorf("Expected offset (1,2,3), got %v, %v", v, ok)
+	}
+	if v, ok := GetMeta[uint64](meta, "FileSize"); !ok || v != 1<<40 {
+		t.Errorf("Expected uint64 file size, got %v, %v", v, ok)
+	}
+
+	// 类型不匹配时返回false
+	if _, ok := GetMeta[float64](meta, "UnitScaleFactor"); ok {
+		t.Error("Expected a float32 value not to be returned as float64")
+	}
+	if _, ok := GetMeta[string](meta, "missing"); ok {
+		t.Error("Expected missing key to return false")
+	}
+}
+
+// TestGetMetaNested 测试读取嵌套元数据
+func TestGetMetaNested(t *testing.T) {
+	meta := buildTestMetadata()
+
+	if v, ok := GetMeta[string](meta, "Pset_WallCommon/FireRating"); !ok || v != "REI60" {
+		t.Errorf("Expected nested fire rating, got %q, %v", v, ok)
+	}
+	if v, ok := GetMetaNumber[float64](meta, "Pset_WallCommon/Thermal/UValue"); !ok || v != 0.24 {
+		t.Errorf("Expected doubly nested U value, got %v, %v", v, ok)
+	}
+	if pset, ok := GetMeta[map[string]Metadata](meta, "Pset_WallCommon"); !ok || len(pset) != 3 {
+		t.Errorf("Expected the property set as a map, got %v", pset)
+	}
+
+	// 本身包含"/"的键优先匹配
+	if v, ok := GetMeta[bool](meta, "a/b"); !ok || !v {
+		t.Error("Expected key containing a slash to match directly")
+	}
+	if _, ok := GetMeta[string](meta, "SourceAsset_Format/x"); ok {
+		t.Error("Expected lookup below a non-map value to fail")
+	}
+}
+
+// TestGetMetaNumber 测试数值类型之间的转换
+func TestGetMetaNumber(t *testing.T) {
+	meta := buildTestMetadata()
+
+	if v, ok := GetMetaNumber[float64](meta, "UnitScaleFactor"); !ok || float32(v) != 2.54 {
+		t.Errorf("Expected 2.54, got %v, %v", v, ok)
+	}
+	if v, ok := GetMetaNumber[int](meta, "Revision"); !ok || v != 7 {
+		t.Errorf("Expected revision 7, got %v, %v", v, ok)
+	}
+	if _, ok := GetMetaNumber[int](meta, "SourceAsset_Format"); ok {
+		t.Error("Expected a string not to convert to a number")
+	}
+}
+
+// TestMetadataTypeString 测试元数据类型名称
+func TestMetadataTypeString(t *testing.T) {
+	tests := []struct {
+		mt   MetadataType
+		want string
+	}{
+		{MetadataTypeBool, "Bool"},
+		{MetadataTypeVec3, "Vec3"},
+		{MetadataTypeMetadata, "Metadata"},
+		{MetadataTypeMAX, "Unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mt.String(); got != tt.want {
+			t.Errorf("Expected %q for %d, got %q", tt.want, tt.mt, got)
+		}
+	}
+}
+
+// TestMetadataGobRoundTrip 测试嵌套元数据能通过gob传给子进程
+func TestMetadataGobRoundTrip(t *testing.T) {
+	scene := &Scene{Metadata: buildTestMetadata()}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(toWireScene(scene)); err != nil {
+		t.Fatalf("Unexpected encode error: %v", err)
+	}
+
+	var ws wireScene
+	if err := gob.NewDecoder(&buf).Decode(&ws); err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	got := ws.scene()
+
+	if v, ok := GetMetaNumber[float64](got.Metadata, "Pset_WallCommon/Thermal/UValue"); !ok || v != 0.24 {
+		t.Errorf("Expected nested metadata to survive the round trip, got %v", got.Metadata)
+	}
+	if v, ok := GetMeta[int32](got.Metadata, "Revision"); !ok || v != 7 {
+		t.Errorf("Expected int32 metadata to survive the round trip, got %v", got.Metadata["Revision"])
+	}
+}
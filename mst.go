@@ -44,10 +44,14 @@ func AssimpToMSTConverter(scene *Scene) *mst.Mesh {
 		Nodes:     make([]*mst.MeshNode, 0),
 	}
 
-	// 转换网格节点
-	for _, aiMesh := range scene.Meshes {
+	// 转换网格节点, nodeIndex记录每个场景网格对应的节点下标,
+	// 被MeshFilter过滤的nil网格没有节点, 记为-1
+	nodeIndex := make([]int, len(scene.Meshes))
+	for i, aiMesh := range scene.Meshes {
+		nodeIndex[i] = -1
 		node := convertMesh(aiMesh)
 		if node != nil {
+			nodeIndex[i] = len(baseMesh.Nodes)
 			baseMesh.Nodes = append(baseMesh.Nodes, node)
 		}
 	}
@@ -56,7 +60,7 @@ func AssimpToMSTConverter(scene *Scene) *mst.Mesh {
 
 	// 处理场景节点层次结构和实例化
 	if scene.RootNode != nil {
-		processNodeHierarchy(scene.RootNode, scene, mesh)
+		processNodeHierarchy(scene.RootNode, nodeIndex, mesh)
 	}
 
 	return mesh
@@ -385,39 +389,25 @@ func convertMesh(aiMesh *Mesh) *mst.MeshNode {
 		}
 	}
 
-	// 转换面
-	if len(aiMesh.Faces) > 0 {
+	// 转换面: 只转换三角形和多边形, 点和线不是面
+	if tris := aiMesh.Triangles(); len(tris) > 0 {
 		faceGroup := &mst.MeshTriangle{
 			Batchid: int32(aiMesh.MaterialIndex),
-			Faces:   make([]*mst.Face, 0, len(aiMesh.Faces)),
+			Faces:   make([]*mst.Face, 0, len(tris)),
 		}
 
-		for _, face := range aiMesh.Faces {
-			if len(face.Indices) >= 3 {
-				// 处理三角面
-				for i := 0; i < len(face.Indices)-2; i++ {
-					mstFace := &mst.Face{
-						Vertex: [3]uint32{
-							uint32(face.Indices[0]),
-							uint32(face.Indices[i+1]),
-							uint32(face.Indices[i+2]),
-						},
-					}
-					faceGroup.Faces = append(faceGroup.Faces, mstFace)
-				}
-			}
+		for _, tri := range tris {
+			faceGroup.Faces = append(faceGroup.Faces, &mst.Face{Vertex: tri})
 		}
 
-		if len(faceGroup.Faces) > 0 {
-			node.FaceGroup = []*mst.MeshTriangle{faceGroup}
-		}
+		node.FaceGroup = []*mst.MeshTriangle{faceGroup}
 	}
 
 	return node
 }
 
-// processNodeHierarchy 处理场景节点层次结构
-func processNodeHierarchy(node *Node, scene *Scene, mesh *mst.Mesh) {
+// processNodeHierarchy 处理场景节点层次结构, nodeIndex将场景网格下标映射到mesh.Nodes
+func processNodeHierarchy(node *Node, nodeIndex []int, mesh *mst.Mesh) {
 	if node == nil {
 		return
 	}
@@ -427,19 +417,19 @@ func processNodeHierarchy(node *Node, scene *Scene, mesh *mst.Mesh) {
 
 	// 处理当前节点的网格实例
 	for _, meshIndex := range node.MeshIndicies {
-		if int(meshIndex) < len(mesh.Nodes) {
+		if int(meshIndex) < len(nodeIndex) && nodeIndex[meshIndex] >= 0 {
 			// 创建实例化网格
 			instance := &mst.InstanceMesh{
 				Transfors: []*mat4d.T{transform},
-				Mesh:      &mst.BaseMesh{Nodes: []*mst.MeshNode{mesh.Nodes[meshIndex]}},
+				Mesh:      &mst.BaseMesh{Nodes: []*mst.MeshNode{mesh.Nodes[nodeIndex[meshIndex]]}},
 			}
-			mesh.Instances = append(mesh.Instances, instance)
+			mesh.InstanceNode = append(mesh.InstanceNode, instance)
 		}
 	}
 
 	// 递归处理子节点
 	for _, child := range node.Children {
-		processNodeHierarchy(child, scene, mesh)
+		processNodeHierarchy(child, nodeIndex, mesh)
 	}
 }
 
@@ -465,7 +455,7 @@ func binaryToUint32(data []byte) uint32 {
 func ImportFileToMST(file string, postProcessFlags PostProcess) (*mst.Mesh, func(), error) {
 	scene, release, err := ImportFile(file, postProcessFlags)
 	if err != nil {
-		return nil, nil, err
+		return nil, func() {}, err
 	}
 
 	mstMesh := AssimpToMSTConverter(scene)
@@ -117,6 +117,35 @@ func TestMeshConversion(t *testing.T) {
 	}
 }
 
+// TestMeshConversionSkipsLines 测试线和点不会被当作三角形转换
+func TestMeshConversionSkipsLines(t *testing.T) {
+	aiMesh := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		Faces: []Face{
+			{Indices: []uint{0, 1}},
+			{Indices: []uint{2}},
+		},
+	}
+
+	converted := convertMesh(aiMesh)
+	if len(converted.Vertices) != 3 {
+		t.Errorf("顶点数量错误，期望3，得到%d", len(converted.Vertices))
+	}
+	if len(converted.FaceGroup) != 0 {
+		t.Errorf("Expected no face groups for lines and points, got %d", len(converted.FaceGroup))
+	}
+
+	// 零拷贝网格的面存放在Indices和FaceOffsets中
+	flat := &Mesh{
+		Vertices:    []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		Indices:     []uint32{0, 1, 2},
+		FaceOffsets: []uint32{0, 3},
+	}
+	if converted := convertMesh(flat); len(converted.FaceGroup) != 1 || len(converted.FaceGroup[0].Faces) != 1 {
+		t.Error("Expected the faces of a zero-copy mesh to be converted")
+	}
+}
+
 // TestComplexSceneConversion 测试复杂场景转换
 func TestComplexSceneConversion(t *testing.T) {
 	// 创建复杂测试场景
@@ -185,6 +214,53 @@ func TestComplexSceneConversion(t *testing.T) {
 	}
 }
 
+// TestFilteredMeshInstances 测试过滤掉的网格不会让实例引用错误的几何体
+func TestFilteredMeshInstances(t *testing.T) {
+	identity := &mat4.T{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+	meshA := &Mesh{
+		Vertices: []vec3.T{{1, 0, 0}, {2, 0, 0}, {1, 1, 0}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}},
+	}
+	meshB := &Mesh{
+		Vertices: []vec3.T{{5, 0, 0}, {6, 0, 0}, {5, 1, 0}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}},
+	}
+
+	// 第一个网格被MeshFilter过滤, 在Meshes中保留为nil
+	scene := &Scene{
+		RootNode: &Node{
+			Name:           "Root",
+			Transformation: identity,
+			MeshIndicies:   []uint{0, 2},
+			Children: []*Node{
+				{Name: "A", Transformation: identity, MeshIndicies: []uint{1}},
+			},
+		},
+		Meshes: []*Mesh{nil, meshA, meshB},
+	}
+
+	converted := AssimpToMSTConverter(scene)
+	if len(converted.Nodes) != 2 {
+		t.Fatalf("Expected 2 mesh nodes, got %d", len(converted.Nodes))
+	}
+	if len(converted.InstanceNode) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(converted.InstanceNode))
+	}
+
+	// 根节点的实例引用meshB, 子节点的实例引用meshA
+	for i, want := range []vec3.T{meshB.Vertices[0], meshA.Vertices[0]} {
+		got := converted.InstanceNode[i].Mesh.Nodes[0]
+		if len(got.Vertices) != 3 || got.Vertices[0] != [3]float32{want[0], want[1], want[2]} {
+			t.Errorf("Instance %d: expected first vertex %v, got %v", i, want, got.Vertices)
+		}
+	}
+}
+
 // TestEmptySceneConversion 测试空场景转换
 func TestEmptySceneConversion(t *testing.T) {
 	scene := &Scene{}
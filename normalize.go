@@ -0,0 +1,580 @@
+package assimp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/quaternion"
+	"github.com/flywave/go3d/vec3"
+)
+
+// Axis is a coordinate axis. Negative values point the other way, e.g.
+// -AxisZ. The zero value means the axis isn't set.
+type Axis int8
+
+const (
+	AxisX Axis = 1
+	AxisY Axis = 2
+	AxisZ Axis = 3
+)
+
+func (a Axis) String() string {
+	switch a {
+	case AxisX, AxisY, AxisZ:
+		return "+" + string("XYZ"[a-1])
+	case -AxisX, -AxisY, -AxisZ:
+		return "-" + string("XYZ"[-a-1])
+	default:
+		return "unset"
+	}
+}
+
+func (a Axis) valid() bool {
+	return a >= -AxisZ && a <= AxisZ && a != 0
+}
+
+func (a Axis) vector() vec3.T {
+	var v vec3.T
+	if a > 0 {
+		v[a-1] = 1
+	} else {
+		v[-a-1] = -1
+	}
+	return v
+}
+
+// axisOf returns the axis v points along. v must be axis aligned.
+func axisOf(v vec3.T) Axis {
+	for i, c := range v {
+		if c > 0.5 {
+			return Axis(i + 1)
+		}
+		if c < -0.5 {
+			return -Axis(i + 1)
+		}
+	}
+	return 0
+}
+
+// Handedness is the handedness of a coordinate system.
+type Handedness int8
+
+const (
+	RightHanded Handedness = iota
+	LeftHanded
+)
+
+func (h Handedness) String() string {
+	if h == LeftHanded {
+		return "left-handed"
+	}
+	return "right-handed"
+}
+
+// CoordinateSystem describes the axis conventions and unit of a scene.
+//
+// UpAxis defaults to +Y. FrontAxis points towards the viewer. The right axis
+// follows from the two and Handedness: front is right x up in right-handed
+// systems and up x right in left-handed ones. If FrontAxis is unset the right
+// axis is +X, or +Y for X-up systems. So the zero value is the right-handed
+// Y-up convention of glTF and of Assimp itself.
+type CoordinateSystem struct {
+	UpAxis     Axis
+	FrontAxis  Axis
+	Handedness Handedness
+	// MetersPerUnit is the length of one unit. 0 keeps the units of the
+	// scene when used as the target of Normalize.
+	MetersPerUnit float64
+}
+
+func (cs CoordinateSystem) String() string {
+	up, front, _, err := cs.basis()
+	if err != nil {
+		return "invalid coordinate system: " + err.Error()
+	}
+
+	unit := "unknown units"
+	if cs.MetersPerUnit > 0 {
+		unit = fmt.Sprintf("%g m/unit", cs.MetersPerUnit)
+	}
+
+	return fmt.Sprintf("up %v, front %v, %v, %s", axisOf(up), axisOf(front), cs.Handedness, unit)
+}
+
+// basis returns the up, front and right vectors of cs.
+func (cs CoordinateSystem) basis() (up, front, right vec3.T, err error) {
+
+	upAxis := cs.UpAxis
+	if upAxis == 0 {
+		upAxis = AxisY
+	}
+	if !upAxis.valid() {
+		return up, front, right, fmt.Errorf("invalid up axis %d", cs.UpAxis)
+	}
+	up = upAxis.vector()
+
+	if cs.FrontAxis == 0 {
+
+		right = AxisX.vector()
+		if upAxis == AxisX || upAxis == -AxisX {
+			right = AxisY.vector()
+		}
+
+		if cs.Handedness == LeftHanded {
+			front = vec3.Cross(&up, &right)
+		} else {
+			front = vec3.Cross(&right, &up)
+		}
+
+		return up, front, right, nil
+	}
+
+	if !cs.FrontAxis.valid() {
+		return up, front, right, fmt.Errorf("invalid front axis %d", cs.FrontAxis)
+	}
+	if cs.FrontAxis == upAxis || cs.FrontAxis == -upAxis {
+		return up, front, right, fmt.Errorf("front axis %v is parallel to up axis %v", cs.FrontAxis, upAxis)
+	}
+	front = cs.FrontAxis.vector()
+
+	if cs.Handedness == LeftHanded {
+		right = vec3.Cross(&front, &up)
+	} else {
+		right = vec3.Cross(&up, &front)
+	}
+
+	return up, front, right, nil
+}
+
+// Metadata keys written by the FBX importer. UnitScaleFactor is in
+// centimeters per unit, the axes are indices with a separate sign.
+const (
+	metaUpAxis          = "UpAxis"
+	metaUpAxisSign      = "UpAxisSign"
+	metaFrontAxis       = "FrontAxis"
+	metaFrontAxisSign   = "FrontAxisSign"
+	metaCoordAxis       = "CoordAxis"
+	metaCoordAxisSign   = "CoordAxisSign"
+	metaUnitScaleFactor = "UnitScaleFactor"
+)
+
+// metaAxis reads an axis stored as an index and a sign, like UpAxis and
+// UpAxisSign.
+func metaAxis(meta map[string]Metadata, indexKey, signKey string, keys *[]string) Axis {
+
+	index, ok := GetMetaNumber[float64](meta, indexKey)
+	if !ok || index < 0 || index > 2 {
+		return 0
+	}
+	*keys = append(*keys, indexKey)
+
+	a := Axis(index) + 1
+
+	if sign, ok := GetMetaNumber[float64](meta, signKey); ok {
+		*keys = append(*keys, signKey)
+		if sign < 0 {
+			a = -a
+		}
+	}
+
+	return a
+}
+
+// DetectCoordinateSystem returns the coordinate system of the scene as
+// described by the UpAxis, FrontAxis, CoordAxis and UnitScaleFactor
+// metadata of FBX files, and the metadata keys it was read from. Missing
+// values default to Assimp's convention, right-handed and Y-up; the unit
+// defaults to meters. Other importers, e.g. Collada and glTF, convert to
+// Assimp's convention themselves.
+//
+// PostProcessGlobalScale converts the scene to meters during import, so
+// UnitScaleFactor is ignored for scenes imported with it.
+func (s *Scene) DetectCoordinateSystem() (cs CoordinateSystem, keys []string) {
+
+	cs.MetersPerUnit = 1
+	keys = []string{}
+
+	if f, ok := GetMetaNumber[float64](s.Metadata, metaUnitScaleFactor); ok && f > 0 && !s.unitScaled {
+		cs.MetersPerUnit = f / 100
+		keys = append(keys, metaUnitScaleFactor)
+	}
+
+	up := metaAxis(s.Metadata, metaUpAxis, metaUpAxisSign, &keys)
+	front := metaAxis(s.Metadata, metaFrontAxis, metaFrontAxisSign, &keys)
+	right := metaAxis(s.Metadata, metaCoordAxis, metaCoordAxisSign, &keys)
+
+	if up == 0 {
+		return cs, keys
+	}
+	cs.UpAxis = up
+
+	if front == 0 || front == up || front == -up {
+		return cs, keys
+	}
+	cs.FrontAxis = front
+
+	if right != 0 {
+		u, f, r := up.vector(), front.vector(), right.vector()
+		c := vec3.Cross(&r, &u)
+		if vec3.Dot(&c, &f) < 0 {
+			cs.Handedness = LeftHanded
+		}
+	}
+
+	return cs, keys
+}
+
+// setMetadata updates the FBX coordinate system metadata the scene has to
+// cs, keeping the types of the entries. Missing keys aren't added.
+func (s *Scene) setMetadata(cs CoordinateSystem) {
+
+	set := func(key string, v float64) {
+
+		m, ok := s.Metadata[key]
+		if !ok {
+			return
+		}
+
+		switch m.Type {
+		case MetadataTypeInt32:
+			m.Value = int32(v)
+		case MetadataTypeUint64:
+			m.Value = uint64(v)
+		case MetadataTypeFloat32:
+			m.Value = float32(v)
+		default:
+			m.Type, m.Value = MetadataTypeFloat64, v
+		}
+
+		s.Metadata[key] = m
+	}
+
+	setAxis := func(indexKey, signKey string, v vec3.T) {
+		a := axisOf(v)
+		sign := 1.0
+		if a < 0 {
+			a, sign = -a, -1
+		}
+		set(indexKey, float64(a-1))
+		set(signKey, sign)
+	}
+
+	up, front, right, _ := cs.basis()
+	setAxis(metaUpAxis, metaUpAxisSign, up)
+	setAxis(metaFrontAxis, metaFrontAxisSign, front)
+	setAxis(metaCoordAxis, metaCoordAxisSign, right)
+
+	set(metaUnitScaleFactor, cs.MetersPerUnit*100)
+}
+
+// NormalizeMode selects how Normalize applies the conversion.
+type NormalizeMode int
+
+const (
+	// NormalizeRootTransform prepends the conversion to the transformation
+	// of the root node, and to its animation keys.
+	NormalizeRootTransform NormalizeMode = iota
+	// NormalizeBake converts the transformations of all nodes, the mesh
+	// vertices, bones and anim meshes, animations, cameras and lights, so
+	// every local space is in the target system.
+	NormalizeBake
+)
+
+// NormalizeReport describes what Normalize detected and did.
+type NormalizeReport struct {
+	// Source is the detected coordinate system of the scene.
+	Source CoordinateSystem
+	// Detected lists the metadata keys Source was read from. It is empty
+	// if the defaults were assumed.
+	Detected []string
+	Target   CoordinateSystem
+	// Conversion is the transformation applied from the source to the
+	// target system.
+	Conversion mat4.T
+	Mode       NormalizeMode
+}
+
+func (r *NormalizeReport) String() string {
+
+	detected := "assumed"
+	if len(r.Detected) > 0 {
+		detected = "from " + strings.Join(r.Detected, ", ")
+	}
+
+	how := "root transform"
+	if r.Mode == NormalizeBake {
+		how = "baked"
+	}
+
+	return fmt.Sprintf("source %v (%s), target %v, %s", r.Source, detected, r.Target, how)
+}
+
+// Normalize converts the scene into the target coordinate system. The source
+// system is read with DetectCoordinateSystem. The coordinate system metadata
+// the scene has is updated to the target, so normalizing a scene whose
+// system is fully described by metadata twice has no further effect. Keys
+// the scene doesn't have aren't added, and scenes without them are assumed
+// to be in the default system again.
+//
+// With NormalizeRootTransform the conversion can't be applied to an animated
+// root node if it changes the handedness, use NormalizeBake then. Baking
+// copies zero-copy meshes into Go memory, see Mesh.Detach.
+func (s *Scene) Normalize(target CoordinateSystem, mode NormalizeMode) (*NormalizeReport, error) {
+
+	source, keys := s.DetectCoordinateSystem()
+
+	srcUp, srcFront, srcRight, err := source.basis()
+	if err != nil {
+		return nil, err
+	}
+	dstUp, dstFront, dstRight, err := target.basis()
+	if err != nil {
+		return nil, err
+	}
+
+	scale := float32(1)
+	if target.MetersPerUnit > 0 {
+		scale = float32(source.MetersPerUnit / target.MetersPerUnit)
+	} else {
+		target.MetersPerUnit = source.MetersPerUnit
+	}
+
+	// rot maps the source right, up and front vectors to the target ones:
+	// rot = dst * transpose(src) with the vectors as columns.
+	src := [3]vec3.T{srcRight, srcUp, srcFront}
+	dst := [3]vec3.T{dstRight, dstUp, dstFront}
+
+	rot := mat4.Ident
+	for c := 0; c < 3; c++ {
+		for r := 0; r < 3; r++ {
+			rot[c][r] = dst[0][r]*src[0][c] + dst[1][r]*src[1][c] + dst[2][r]*src[2][c]
+		}
+	}
+
+	conv := rot
+	for c := 0; c < 3; c++ {
+		for r := 0; r < 3; r++ {
+			conv[c][r] = rot[c][r] * scale
+		}
+	}
+
+	report := &NormalizeReport{
+		Source:     source,
+		Detected:   keys,
+		Target:     target,
+		Conversion: conv,
+		Mode:       mode,
+	}
+
+	switch mode {
+	case NormalizeRootTransform:
+		err = s.normalizeRoot(&conv, &rot, scale)
+	case NormalizeBake:
+		s.normalizeBake(&conv, &rot, scale)
+	default:
+		err = fmt.Errorf("invalid normalize mode %d", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.setMetadata(target)
+	// UnitScaleFactor now holds the unit of the converted data.
+	s.unitScaled = false
+	s.InvalidateBounds()
+
+	return report, nil
+}
+
+// rotationQuat returns the rotation conjugating by rot is equivalent to.
+// Conjugating by a reflection is the same as conjugating by its negation,
+// which is a rotation.
+func rotationQuat(rot *mat4.T) quaternion.T {
+	m := *rot
+	if m.Determinant3x3() < 0 {
+		for c := 0; c < 3; c++ {
+			for r := 0; r < 3; r++ {
+				m[c][r] = -m[c][r]
+			}
+		}
+	}
+	return m.Quaternion()
+}
+
+func (s *Scene) normalizeRoot(conv, rot *mat4.T, scale float32) error {
+
+	if s.RootNode == nil {
+		return nil
+	}
+
+	var channels []*NodeAnim
+	for _, a := range s.Animations {
+		for _, c := range a.channels {
+			if c.name == s.RootNode.Name {
+				channels = append(channels, c)
+			}
+		}
+	}
+
+	if len(channels) > 0 && rot.Determinant3x3() < 0 {
+		return errors.New("assimp: can't change the handedness of an animated root node, use NormalizeBake")
+	}
+
+	var t mat4.T
+	t.AssignMul(conv, s.RootNode.localTransform())
+	s.RootNode.Transformation = &t
+
+	// The keys replace the root transformation: convert T*R*S into
+	// (conv*T)*(rot*R)*(scale*S).
+	q := rotationQuat(rot)
+	for _, c := range channels {
+		for i := range c.positionKeys {
+			c.positionKeys[i].value = transformPoint(conv, c.positionKeys[i].value)
+		}
+		for i := range c.rotationKeys {
+			c.rotationKeys[i].value = quaternion.Mul(&q, &c.rotationKeys[i].value)
+		}
+		for i := range c.scalingKeys {
+			c.scalingKeys[i].value.Scale(scale)
+		}
+	}
+
+	return nil
+}
+
+// conjugate returns conv * m * inverse(conv).
+func conjugate(conv, inv, m *mat4.T) mat4.T {
+	var t, r mat4.T
+	t.AssignMul(m, inv)
+	r.AssignMul(conv, &t)
+	return r
+}
+
+func (s *Scene) normalizeBake(conv, rot *mat4.T, scale float32) {
+
+	inv := conv.Inverted()
+	mirrored := rot.Determinant3x3() < 0
+
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		if n.Transformation != nil {
+			t := conjugate(conv, &inv, n.Transformation)
+			n.Transformation = &t
+		}
+		for _, c := range n.Children {
+			if c != nil {
+				visit(c)
+			}
+		}
+	}
+	if s.RootNode != nil {
+		visit(s.RootNode)
+	}
+
+	dir := func(d vec3.T) vec3.T {
+		return transformDir(rot, d)
+	}
+	dirs := func(ds []vec3.T) {
+		for i := range ds {
+			ds[i] = dir(ds[i])
+		}
+	}
+	points := func(ps []vec3.T) {
+		for i := range ps {
+			ps[i] = transformPoint(conv, ps[i])
+		}
+	}
+
+	for _, m := range s.Meshes {
+
+		if m == nil {
+			continue
+		}
+		m.Detach()
+
+		points(m.Vertices)
+		dirs(m.Normals)
+		dirs(m.Tangents)
+		dirs(m.BitTangents)
+
+		for _, am := range m.AnimMeshes {
+			points(am.Vertices)
+			dirs(am.Normals)
+			dirs(am.Tangents)
+			dirs(am.BitTangents)
+		}
+
+		for _, b := range m.Bones {
+			b.OffsetMatrix = conjugate(conv, &inv, &b.OffsetMatrix)
+		}
+
+		if mirrored {
+			reverseWinding(m)
+		}
+
+		if m.AABB != (AABB{}) {
+			m.AABB = m.ComputeAABB()
+		}
+	}
+
+	// Node animation keys are local transformations, they are conjugated
+	// like the node transformations.
+	q := rotationQuat(rot)
+	qi := q.Inverted()
+	for _, a := range s.Animations {
+		for _, c := range a.channels {
+			for i := range c.positionKeys {
+				c.positionKeys[i].value = transformPoint(conv, c.positionKeys[i].value)
+			}
+			for i := range c.rotationKeys {
+				c.rotationKeys[i].value = quaternion.Mul3(&q, &c.rotationKeys[i].value, &qi)
+			}
+			for i := range c.scalingKeys {
+				v := c.scalingKeys[i].value
+				for r := 0; r < 3; r++ {
+					c.scalingKeys[i].value[r] = absf(rot[0][r])*v[0] + absf(rot[1][r])*v[1] + absf(rot[2][r])*v[2]
+				}
+			}
+		}
+	}
+
+	// Attenuation is over distance, so it scales with the unit.
+	for _, l := range s.Lights {
+		l.position = transformPoint(conv, l.position)
+		l.direction = dir(l.direction)
+		l.attenuationLinear /= scale
+		l.attenuationQuadratic /= scale * scale
+	}
+
+	for _, c := range s.Cameras {
+		c.position = transformPoint(conv, c.position)
+		c.lookAt = dir(c.lookAt)
+		c.up = dir(c.up)
+		c.clipPlaneNear *= scale
+		c.clipPlaneFar *= scale
+	}
+}
+
+func absf(f float32) float32 {
+	return float32(math.Abs(float64(f)))
+}
+
+// reverseWinding reverses the index order of every face of m.
+func reverseWinding(m *Mesh) {
+
+	for _, f := range m.Faces {
+		for i, j := 0, len(f.Indices)-1; i < j; i, j = i+1, j-1 {
+			f.Indices[i], f.Indices[j] = f.Indices[j], f.Indices[i]
+		}
+	}
+
+	for f := 0; f+1 < len(m.FaceOffsets); f++ {
+		idx := m.Indices[m.FaceOffsets[f]:m.FaceOffsets[f+1]]
+		for i, j := 0, len(idx)-1; i < j; i, j = i+1, j-1 {
+			idx[i], idx[j] = idx[j], idx[i]
+		}
+	}
+}
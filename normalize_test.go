@@ -0,0 +1,244 @@
+package assimp
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/quaternion"
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildZUpScene 构建一个3ds Max风格的FBX场景: Z轴向上, 正面为-Y, 单位为厘米
+func buildZUpScene() *Scene {
+	rootT := mat4.Ident
+	rootT.SetTranslation(&vec3.T{0, 0, 100})
+
+	root := &Node{Name: "root", Transformation: &rootT}
+	box := &Node{Name: "box", Parent: root, Transformation: &mat4.T{
+		{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1},
+	}, MeshIndicies: []uint{0}}
+	root.Children = []*Node{box}
+
+	mesh := triangleMesh("tri", 0)
+	mesh.Vertices = []vec3.T{{0, 0, 0}, {100, 0, 0}, {0, 100, 0}}
+
+	return &Scene{
+		RootNode: root,
+		Meshes:   []*Mesh{mesh},
+		Metadata: map[string]Metadata{
+			"UpAxis":          {Type: MetadataTypeInt32, Value: int32(2)},
+			"UpAxisSign":      {Type: MetadataTypeInt32, Value: int32(1)},
+			"FrontAxis":       {Type: MetadataTypeInt32, Value: int32(1)},
+			"FrontAxisSign":   {Type: MetadataTypeInt32, Value: int32(-1)},
+			"CoordAxis":       {Type: MetadataTypeInt32, Value: int32(0)},
+			"CoordAxisSign":   {Type: MetadataTypeInt32, Value: int32(1)},
+			"UnitScaleFactor": {Type: MetadataTypeFloat32, Value: float32(1)},
+		},
+		Animations: []*Animation{{name: "spin", channels: []*NodeAnim{{
+			name:         "box",
+			positionKeys: []VectorKey{{value: vec3.T{0, 0, 50}}},
+			rotationKeys: []QuatKey{{value: quaternion.FromZAxisAngle(math.Pi / 2)}},
+			scalingKeys:  []VectorKey{{value: vec3.T{1, 2, 3}}},
+		}}}},
+		Lights:  []*Light{{name: "lamp", position: vec3.T{0, 0, 200}, direction: vec3.T{0, 0, -1}, attenuationLinear: 1}},
+		Cameras: []*Camera{{name: "cam", position: vec3.T{0, -500, 0}, lookAt: vec3.T{0, 1, 0}, up: vec3.T{0, 0, 1}, clipPlaneNear: 10}},
+	}
+}
+
+// worldVertex 返回网格0的第i个顶点在box节点下的世界坐标
+func worldVertex(s *Scene, i int) vec3.T {
+	world := s.FindNode("box").WorldTransform()
+	return transformPoint(&world, s.Meshes[0].Vertices[i])
+}
+
+// TestDetectCoordinateSystem 测试从FBX元数据中识别坐标系
+func TestDetectCoordinateSystem(t *testing.T) {
+	cs, keys := buildZUpScene().DetectCoordinateSystem()
+
+	if cs.UpAxis != AxisZ || cs.FrontAxis != -AxisY || cs.Handedness != RightHanded || cs.MetersPerUnit != 0.01 {
+		t.Errorf("Expected Z-up, -Y front, right-handed centimeters, got %v", cs)
+	}
+	if len(keys) != 7 {
+		t.Errorf("Expected 7 metadata keys to be used, got %v", keys)
+	}
+
+	cs, keys = (&Scene{}).DetectCoordinateSystem()
+	if cs != (CoordinateSystem{MetersPerUnit: 1}) || len(keys) != 0 {
+		t.Errorf("Expected the default coordinate system, got %v from %v", cs, keys)
+	}
+
+	if s := (CoordinateSystem{}).String(); s != "up +Y, front +Z, right-handed, unknown units" {
+		t.Errorf("Unexpected string %q", s)
+	}
+}
+
+// TestNormalizeRootTransform 测试通过根节点变换进行转换
+func TestNormalizeRootTransform(t *testing.T) {
+	scene := buildZUpScene()
+
+	report, err := scene.Normalize(CoordinateSystem{UpAxis: AxisY, MetersPerUnit: 1}, NormalizeRootTransform)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// (100,0,100)厘米, Z向上 -> (1,1,0)米, Y向上
+	if p := worldVertex(scene, 1); !approxVec3(p, vec3.T{1, 1, 0}) {
+		t.Errorf("Expected world vertex (1,1,0), got %v", p)
+	}
+	if scene.Meshes[0].Vertices[1] != (vec3.T{100, 0, 0}) {
+		t.Error("Expected vertices to be left unchanged")
+	}
+
+	if !strings.Contains(report.String(), "UpAxis") || report.Source.UpAxis != AxisZ {
+		t.Errorf("Expected the report to name the detected metadata, got %q", report)
+	}
+
+	// 元数据已更新, 再次转换不做任何事
+	report, err = scene.Normalize(CoordinateSystem{MetersPerUnit: 1}, NormalizeRootTransform)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Conversion != mat4.Ident {
+		t.Errorf("Expected identity conversion the second time, got %v", report.Conversion)
+	}
+	if p := worldVertex(scene, 1); !approxVec3(p, vec3.T{1, 1, 0}) {
+		t.Errorf("Expected world vertex to stay at (1,1,0), got %v", p)
+	}
+}
+
+// TestNormalizeBake 测试将转换烘焙到顶点、动画、灯光和相机中
+func TestNormalizeBake(t *testing.T) {
+	scene := buildZUpScene()
+
+	if _, err := scene.Normalize(CoordinateSystem{MetersPerUnit: 1}, NormalizeBake); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if p := worldVertex(scene, 1); !approxVec3(p, vec3.T{1, 1, 0}) {
+		t.Errorf("Expected world vertex (1,1,0), got %v", p)
+	}
+	if v := scene.Meshes[0].Vertices[2]; !approxVec3(v, vec3.T{0, 0, -1}) {
+		t.Errorf("Expected baked vertex (0,0,-1), got %v", v)
+	}
+	if n := scene.Meshes[0].Normals[0]; !approxVec3(n, vec3.T{0, 1, 0}) {
+		t.Errorf("Expected baked normal (0,1,0), got %v", n)
+	}
+	if *scene.FindNode("box").Transformation != mat4.Ident {
+		t.Error("Expected identity node transformation to stay the identity")
+	}
+
+	ch := scene.Animations[0].channels[0]
+	if p := ch.positionKeys[0].value; !approxVec3(p, vec3.T{0, 0.5, 0}) {
+		t.Errorf("Expected position key (0,0.5,0), got %v", p)
+	}
+	want := quaternion.FromYAxisAngle(math.Pi / 2)
+	if got := ch.rotationKeys[0].value; math.Abs(float64(quaternion.Dot(&got, &want))) < 0.9999 {
+		t.Errorf("Expected rotation about Y, got %v", got)
+	}
+	if s := ch.scalingKeys[0].value; !approxVec3(s, vec3.T{1, 3, 2}) {
+		t.Errorf("Expected scaling key (1,3,2), got %v", s)
+	}
+
+	l := scene.Lights[0]
+	if !approxVec3(l.position, vec3.T{0, 2, 0}) || !approxVec3(l.direction, vec3.T{0, -1, 0}) || math.Abs(float64(l.attenuationLinear-100)) > 1e-3 {
+		t.Errorf("Unexpected baked light %+v", l)
+	}
+
+	c := scene.Cameras[0]
+	if !approxVec3(c.position, vec3.T{0, 0, 5}) || !approxVec3(c.lookAt, vec3.T{0, 0, -1}) || !approxVec3(c.up, vec3.T{0, 1, 0}) {
+		t.Errorf("Unexpected baked camera %+v", c)
+	}
+	if math.Abs(float64(c.clipPlaneNear-0.1)) > 1e-6 {
+		t.Errorf("Expected near plane 0.1, got %v", c.clipPlaneNear)
+	}
+
+	if v := scene.Metadata["UnitScaleFactor"]; v.Type != MetadataTypeFloat32 || v.Value != float32(100) {
+		t.Errorf("Expected UnitScaleFactor metadata to be updated in place, got %v", v)
+	}
+}
+
+// TestNormalizeHandedness 测试改变手性时翻转面的顶点顺序
+func TestNormalizeHandedness(t *testing.T) {
+	scene := buildZUpScene()
+
+	report, err := scene.Normalize(CoordinateSystem{Handedness: LeftHanded}, NormalizeBake)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Conversion.Determinant3x3() >= 0 {
+		t.Error("Expected a mirroring conversion")
+	}
+	if report.Target.MetersPerUnit != 0.01 {
+		t.Errorf("Expected units to be kept, got %v", report.Target.MetersPerUnit)
+	}
+
+	if f := scene.Meshes[0].Faces[0].Indices; f[0] != 2 || f[2] != 0 {
+		t.Errorf("Expected reversed winding, got %v", f)
+	}
+
+	// 动画的根节点不能通过根变换改变手性
+	scene = buildZUpScene()
+	scene.Animations[0].channels[0].name = "root"
+	if _, err := scene.Normalize(CoordinateSystem{Handedness: LeftHanded}, NormalizeRootTransform); err == nil {
+		t.Error("Expected an error for an animated root node")
+	}
+
+	if _, err := scene.Normalize(CoordinateSystem{UpAxis: AxisY, FrontAxis: -AxisY}, NormalizeBake); err == nil {
+		t.Error("Expected an error for parallel up and front axes")
+	}
+}
+
+// TestNormalizeMetadataKeys 测试只更新场景中已有的坐标系元数据
+func TestNormalizeMetadataKeys(t *testing.T) {
+	scene := buildZUpScene()
+	scene.Metadata = nil
+
+	if _, err := scene.Normalize(CoordinateSystem{UpAxis: AxisZ}, NormalizeRootTransform); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(scene.Metadata) != 0 {
+		t.Errorf("Expected no metadata to be added, got %v", scene.Metadata)
+	}
+
+	scene = buildZUpScene()
+	scene.Metadata = map[string]Metadata{"UnitScaleFactor": {Type: MetadataTypeFloat64, Value: 1.0}}
+
+	report, err := scene.Normalize(CoordinateSystem{MetersPerUnit: 1}, NormalizeRootTransform)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Detected) != 1 || len(scene.Metadata) != 1 || scene.Metadata["UnitScaleFactor"].Value != 100.0 {
+		t.Errorf("Expected only UnitScaleFactor to be updated, got %v", scene.Metadata)
+	}
+}
+
+// TestNormalizeGlobalScale 测试PostProcessGlobalScale已应用单位时不再重复缩放
+func TestNormalizeGlobalScale(t *testing.T) {
+	scene := buildZUpScene()
+	scene.unitScaled = true
+
+	cs, keys := scene.DetectCoordinateSystem()
+	if cs.MetersPerUnit != 1 || len(keys) != 6 {
+		t.Errorf("Expected UnitScaleFactor to be ignored, got %v from %v", cs, keys)
+	}
+
+	if _, err := scene.Normalize(CoordinateSystem{MetersPerUnit: 1}, NormalizeRootTransform); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p := worldVertex(scene, 1); !approxVec3(p, vec3.T{100, 100, 0}) {
+		t.Errorf("Expected world vertex (100,100,0) without scaling, got %v", p)
+	}
+	if v := scene.Metadata["UnitScaleFactor"]; v.Value != float32(100) {
+		t.Errorf("Expected UnitScaleFactor to describe the converted data, got %v", v)
+	}
+
+	report, err := scene.Normalize(CoordinateSystem{MetersPerUnit: 1}, NormalizeRootTransform)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Conversion != mat4.Ident {
+		t.Errorf("Expected identity conversion the second time, got %v", report.Conversion)
+	}
+}
@@ -0,0 +1,173 @@
+package assimp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// NormalWeighting is how GenerateNormals weights the normals of the faces
+// around a vertex.
+type NormalWeighting int
+
+const (
+	// NormalWeightAngle weights each face by its angle at the vertex, which
+	// doesn't depend on how the faces are tessellated.
+	NormalWeightAngle NormalWeighting = iota
+	// NormalWeightArea weights each face by its area.
+	NormalWeightArea
+	// NormalWeightUniform weights all faces the same.
+	NormalWeightUniform
+)
+
+func (w NormalWeighting) String() string {
+
+	switch w {
+	case NormalWeightAngle:
+		return "Angle"
+	case NormalWeightArea:
+		return "Area"
+	case NormalWeightUniform:
+		return "Uniform"
+	default:
+		return "Unknown"
+	}
+}
+
+// NormalOptions controls Mesh.GenerateNormals.
+type NormalOptions struct {
+	// Flat gives every face its own normal. The other options except
+	// Preserve are ignored.
+	Flat bool
+
+	// CreaseAngle is the largest angle in radians between two faces that
+	// are smoothed across their shared vertices. Vertices on sharper edges
+	// are split. 0 smooths across all edges.
+	CreaseAngle float32
+
+	Weighting NormalWeighting
+
+	// Preserve selects faces, by index into the faces of the mesh, whose
+	// corners keep their existing normals. The faces still contribute to
+	// the normals of their neighbours.
+	Preserve func(face int) bool
+}
+
+// GenerateNormals computes the vertex normals of the faces with three or
+// more corners according to opts. Faces are smoothed across vertices at
+// the same position, so meshes from importers that don't share vertices,
+// like STL, get smooth normals too.
+//
+// Where the corners sharing a vertex get different normals, on crease edges
+// or for flat shading, the vertex is split and the faces, bones and anim
+// meshes are updated. Vertices only used by lines and points keep their
+// normal, or get a zero one.
+func (m *Mesh) GenerateNormals(opts NormalOptions) error {
+
+	n := len(m.Vertices)
+
+	faces := m.polygonFaces()
+	if len(faces) == 0 {
+		return fmt.Errorf("assimp: mesh %q has no triangles", m.Name)
+	}
+	if opts.Preserve != nil && len(m.Normals) < n {
+		return fmt.Errorf("assimp: mesh %q has no normals to preserve", m.Name)
+	}
+	if opts.Weighting < NormalWeightAngle || opts.Weighting > NormalWeightUniform {
+		return fmt.Errorf("assimp: invalid normal weighting %d", opts.Weighting)
+	}
+
+	m.Detach()
+
+	// Normal and area of each face, from Newell's method so polygons that
+	// aren't planar work too.
+	normals := make([]vec3.T, len(faces))
+	areas := make([]float32, len(faces))
+	for f, face := range faces {
+		var sum vec3.T
+		for i, v := range face.indices {
+			a, b := m.Vertices[v], m.Vertices[face.indices[(i+1)%len(face.indices)]]
+			sum[0] += (a[1] - b[1]) * (a[2] + b[2])
+			sum[1] += (a[2] - b[2]) * (a[0] + b[0])
+			sum[2] += (a[0] - b[0]) * (a[1] + b[1])
+		}
+		areas[f] = sum.Length() / 2
+		normals[f] = sum.Normalized()
+	}
+
+	// The corners of all faces at each position.
+	type corner struct{ face, index int }
+	byPos := make(map[vec3.T][]corner)
+	for f, face := range faces {
+		for i, v := range face.indices {
+			byPos[m.Vertices[v]] = append(byPos[m.Vertices[v]], corner{f, i})
+		}
+	}
+
+	cornerAngle := func(c corner) float32 {
+		idx := faces[c.face].indices
+		p := m.Vertices[idx[c.index]]
+		prev := m.Vertices[idx[(c.index+len(idx)-1)%len(idx)]]
+		next := m.Vertices[idx[(c.index+1)%len(idx)]]
+
+		e1 := vec3.Sub(&prev, &p)
+		e2 := vec3.Sub(&next, &p)
+		e1.Normalize()
+		e2.Normalize()
+
+		cos := min(max(vec3.Dot(&e1, &e2), -1), 1)
+		return float32(math.Acos(float64(cos)))
+	}
+
+	cosCrease := float32(math.Cos(float64(opts.CreaseAngle)))
+	oldNormals := m.Normals
+
+	values, assigned := splitCorners(m, faces, func(f, i int) vec3.T {
+		v := faces[f].indices[i]
+
+		switch {
+		case opts.Preserve != nil && opts.Preserve(faces[f].face):
+			return oldNormals[v]
+		case opts.Flat:
+			return normals[f]
+		}
+
+		// Degenerate faces have no normal to compare, they take all of
+		// their neighbours.
+		crease := opts.CreaseAngle > 0 && normals[f] != (vec3.T{})
+
+		var sum vec3.T
+		for _, c := range byPos[m.Vertices[v]] {
+			nf := normals[c.face]
+			if crease && c.face != f && vec3.Dot(&normals[f], &nf) < cosCrease {
+				continue
+			}
+
+			var w float32
+			switch opts.Weighting {
+			case NormalWeightAngle:
+				w = cornerAngle(c)
+			case NormalWeightArea:
+				w = areas[c.face]
+			case NormalWeightUniform:
+				w = 1
+			}
+
+			nf.Scale(w)
+			sum.Add(&nf)
+		}
+
+		return sum.Normalized()
+	})
+
+	// Vertices outside the faces keep their normal.
+	for v, ok := range assigned {
+		if !ok && v < len(m.Normals) {
+			values[v] = m.Normals[v]
+		}
+	}
+	m.Normals = values
+
+	return nil
+}
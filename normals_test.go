@@ -0,0 +1,156 @@
+package assimp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildFoldMesh 构建一个XY平面上的三角形(法线+Z)和两个YZ平面上的三角形(法线+X),
+// 它们共享顶点0. 在顶点0处三个三角形的夹角为90, 45和45度, 面积为0.5, 0.5和1
+func buildFoldMesh() *Mesh {
+	return &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 1, 1}, {0, 0, 2}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}, {Indices: []uint{0, 2, 3}}, {Indices: []uint{0, 3, 4}}},
+	}
+}
+
+// normalize3 返回归一化的向量
+func normalize3(x, y, z float32) vec3.T {
+	v := vec3.T{x, y, z}
+	return v.Normalized()
+}
+
+// TestGenerateNormalsWeighting 测试三种加权方式
+func TestGenerateNormalsWeighting(t *testing.T) {
+	tests := []struct {
+		weighting NormalWeighting
+		want      vec3.T
+	}{
+		{NormalWeightAngle, normalize3(1, 0, 1)},
+		{NormalWeightArea, normalize3(3, 0, 1)},
+		{NormalWeightUniform, normalize3(2, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		mesh := buildFoldMesh()
+		if err := mesh.GenerateNormals(NormalOptions{Weighting: tt.weighting}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(mesh.Vertices) != 5 {
+			t.Errorf("Expected no vertices to be split, got %d", len(mesh.Vertices))
+		}
+		if n := mesh.Normals[0]; !approxVec3(n, tt.want) {
+			t.Errorf("Expected %v weighted normal %v, got %v", tt.weighting, tt.want, n)
+		}
+		if n := mesh.Normals[4]; !approxVec3(n, vec3.T{1, 0, 0}) {
+			t.Errorf("Expected normal (1,0,0) for vertex 4, got %v", n)
+		}
+	}
+}
+
+// TestGenerateNormalsCrease 测试折痕角和平面着色
+func TestGenerateNormalsCrease(t *testing.T) {
+	mesh := buildFoldMesh()
+	if err := mesh.GenerateNormals(NormalOptions{CreaseAngle: math.Pi / 4}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 顶点0和2在90度的边上被拆分
+	if len(mesh.Vertices) != 7 {
+		t.Fatalf("Expected 7 vertices, got %d", len(mesh.Vertices))
+	}
+	for f, want := range []vec3.T{{0, 0, 1}, {1, 0, 0}, {1, 0, 0}} {
+		for _, v := range mesh.Faces[f].Indices {
+			if !approxVec3(mesh.Normals[v], want) {
+				t.Errorf("Expected normal %v for face %d, got %v at vertex %d", want, f, mesh.Normals[v], v)
+			}
+		}
+	}
+
+	// 折痕角大于90度时不拆分
+	mesh = buildFoldMesh()
+	if err := mesh.GenerateNormals(NormalOptions{CreaseAngle: math.Pi * 0.6}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mesh.Vertices) != 5 {
+		t.Errorf("Expected no vertices to be split, got %d", len(mesh.Vertices))
+	}
+
+	mesh = buildFoldMesh()
+	if err := mesh.GenerateNormals(NormalOptions{Flat: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mesh.Vertices) != 7 || !approxVec3(mesh.Normals[mesh.Faces[0].Indices[0]], vec3.T{0, 0, 1}) {
+		t.Errorf("Expected flat normals on 7 vertices, got %d", len(mesh.Vertices))
+	}
+}
+
+// TestGenerateNormalsUnshared 测试不共享顶点的网格(如STL)按位置平滑
+func TestGenerateNormalsUnshared(t *testing.T) {
+	mesh := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 0}, {0, 1, 0}, {0, 1, 1}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}, {Indices: []uint{3, 4, 5}}},
+	}
+
+	if err := mesh.GenerateNormals(NormalOptions{Weighting: NormalWeightUniform}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := normalize3(1, 0, 1)
+	for _, v := range []int{0, 2, 3, 4} {
+		if !approxVec3(mesh.Normals[v], want) {
+			t.Errorf("Expected smooth normal %v at vertex %d, got %v", want, v, mesh.Normals[v])
+		}
+	}
+}
+
+// TestGenerateNormalsPreserve 测试保留选定面的法线
+func TestGenerateNormalsPreserve(t *testing.T) {
+	mesh := buildFoldMesh()
+	mesh.Normals = []vec3.T{{0, 0, -1}, {0, 0, -1}, {0, 0, -1}, {0, 0, -1}, {0, 0, -1}}
+	// 只被线段使用的顶点5保留原法线, 线段是第0个面
+	mesh.Vertices = append(mesh.Vertices, vec3.T{5, 5, 5})
+	mesh.Normals = append(mesh.Normals, vec3.T{0, 1, 0})
+	mesh.Faces = append([]Face{{Indices: []uint{4, 5}}}, mesh.Faces...)
+
+	err := mesh.GenerateNormals(NormalOptions{
+		Weighting: NormalWeightUniform,
+		Preserve:  func(face int) bool { return face == 1 },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, v := range mesh.Faces[1].Indices {
+		if mesh.Normals[v] != (vec3.T{0, 0, -1}) {
+			t.Errorf("Expected preserved normal at vertex %d, got %v", v, mesh.Normals[v])
+		}
+	}
+
+	// 被保留的面仍参与相邻顶点的平滑
+	if n := mesh.Normals[mesh.Faces[2].Indices[0]]; !approxVec3(n, normalize3(2, 0, 1)) {
+		t.Errorf("Expected smooth normal at the shared vertex, got %v", n)
+	}
+	if mesh.Normals[5] != (vec3.T{0, 1, 0}) {
+		t.Errorf("Expected the line vertex to keep its normal, got %v", mesh.Normals[5])
+	}
+}
+
+// TestGenerateNormalsErrors 测试无效的输入
+func TestGenerateNormalsErrors(t *testing.T) {
+	mesh := buildFoldMesh()
+	if err := mesh.GenerateNormals(NormalOptions{Preserve: func(int) bool { return true }}); err == nil {
+		t.Error("Expected an error when preserving normals of a mesh without normals")
+	}
+	if err := mesh.GenerateNormals(NormalOptions{Weighting: NormalWeightUniform + 1}); err == nil {
+		t.Error("Expected an error for an invalid weighting")
+	}
+
+	lines := &Mesh{Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}}, Faces: []Face{{Indices: []uint{0, 1}}}}
+	if err := lines.GenerateNormals(NormalOptions{}); err == nil {
+		t.Error("Expected an error for a mesh without triangles")
+	}
+}
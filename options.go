@@ -0,0 +1,378 @@
+package assimp
+
+/*
+#cgo linux CFLAGS: -I ./lib/linux
+#cgo darwin,amd64 CFLAGS: -I ./lib/darwin
+#cgo darwin,arm64 CFLAGS: -I ./lib/darwin_arm
+
+#include <stdlib.h>
+#include <string.h>
+#include <assimp/cimport.h>
+*/
+import "C"
+import (
+	"errors"
+	"log/slog"
+	"unsafe"
+
+	"github.com/flywave/go3d/mat4"
+)
+
+// Importer property keys from assimp/config.h. They can be passed to the raw
+// ImportOptions setters for anything the typed fields don't cover.
+const (
+	ConfigSmoothingAngle            = "PP_GSN_MAX_SMOOTHING_ANGLE"
+	ConfigTangentSmoothingAngle     = "PP_CT_MAX_SMOOTHING_ANGLE"
+	ConfigTangentUVChannel          = "PP_CT_TEXTURE_CHANNEL_INDEX"
+	ConfigRemoveComponents          = "PP_RVC_FLAGS"
+	ConfigSplitLargeMeshTriangles   = "PP_SLM_TRIANGLE_LIMIT"
+	ConfigSplitLargeMeshVertices    = "PP_SLM_VERTEX_LIMIT"
+	ConfigMaxBoneWeights            = "PP_LBW_MAX_WEIGHTS"
+	ConfigMaxBonesPerMesh           = "PP_SBBC_MAX_BONES"
+	ConfigRemoveDegenerates         = "PP_FD_REMOVE"
+	ConfigRemovePrimitiveTypes      = "PP_SBP_REMOVE"
+	ConfigCacheLocalitySize         = "PP_ICL_PTCACHE_SIZE"
+	ConfigGlobalScale               = "GLOBAL_SCALE_FACTOR"
+	ConfigNoSkeletonMeshes          = "IMPORT_NO_SKELETON_MESHES"
+	ConfigFBXPreservePivots         = "IMPORT_FBX_PRESERVE_PIVOTS"
+	ConfigFBXReadAnimations         = "IMPORT_FBX_READ_ANIMATIONS"
+	ConfigFBXStrictMode             = "IMPORT_FBX_STRICT_MODE"
+	ConfigFBXConvertToMeters        = "AI_CONFIG_FBX_CONVERT_TO_M"
+	ConfigColladaIgnoreUpDirection  = "IMPORT_COLLADA_IGNORE_UP_DIRECTION"
+	ConfigPreTransformKeepHierarchy = "PP_PTV_KEEP_HIERARCHY"
+)
+
+type propertyKind int
+
+const (
+	propertyInt propertyKind = iota
+	propertyFloat
+	propertyString
+	propertyMatrix
+)
+
+type property struct {
+	name string
+	kind propertyKind
+	i    int
+	f    float32
+	s    string
+	m    mat4.T
+}
+
+// ImportOptions configures an import through Assimp's property store. The zero
+// value of every typed field leaves the corresponding Assimp default in place;
+// booleans whose Assimp default is true are pointers so they can be turned off
+// (see Bool).
+type ImportOptions struct {
+	// SmoothingAngle is the crease angle in degrees used by
+	// PostProcessGenSmoothNormals. Assimp defaults to 175.
+	SmoothingAngle float32
+	// TangentSmoothingAngle is the maximum angle in degrees between tangents
+	// that PostProcessCalcTangentSpace smooths. Assimp defaults to 45.
+	TangentSmoothingAngle float32
+	// TangentUVChannel is the UV channel PostProcessCalcTangentSpace uses.
+	TangentUVChannel int
+
+	// RemoveComponents lists what PostProcessRemoveComponent strips.
+	RemoveComponents Component
+	// RemovePrimitiveTypes lists the primitive types PostProcessSortByPType
+	// drops from the scene.
+	RemovePrimitiveTypes PrimitiveType
+	// RemoveDegenerates makes PostProcessFindDegenerates drop degenerate
+	// primitives instead of converting them to lines and points.
+	RemoveDegenerates bool
+
+	// SplitLargeMeshTriangles and SplitLargeMeshVertices are the limits used
+	// by PostProcessSplitLargeMeshes. Assimp defaults to 1000000 for both.
+	SplitLargeMeshTriangles int
+	SplitLargeMeshVertices  int
+
+	// MaxBoneWeights is the per vertex limit of PostProcessLimitBoneWeights.
+	MaxBoneWeights int
+	// MaxBonesPerMesh is the per mesh limit of PostProcessSplitByBoneCount.
+	MaxBonesPerMesh int
+
+	// CacheLocalitySize is the vertex cache size PostProcessImproveCacheLocality
+	// optimizes for.
+	CacheLocalitySize int
+
+	// GlobalScale is the factor applied by PostProcessGlobalScale.
+	GlobalScale float32
+
+	// NoSkeletonMeshes disables the dummy meshes generated for files that
+	// contain skeletons but no geometry.
+	NoSkeletonMeshes bool
+
+	// FBXPreservePivots keeps FBX pivots as extra nodes. Assimp defaults to true.
+	FBXPreservePivots *bool
+	// FBXReadAnimations controls whether FBX animations are imported. Assimp
+	// defaults to true.
+	FBXReadAnimations *bool
+	// FBXStrictMode rejects FBX files that don't follow the 2013 spec.
+	FBXStrictMode bool
+	// FBXConvertToMeters converts FBX units from centimeters to meters.
+	FBXConvertToMeters bool
+
+	// ColladaIgnoreUpDirection ignores the <up_axis> of Collada files.
+	ColladaIgnoreUpDirection bool
+
+	// Logger receives Assimp's log output for this import instead of the
+	// logger set with SetLogger.
+	Logger *slog.Logger
+	// VerboseLogging enables Assimp's verbose logging for this import.
+	VerboseLogging bool
+
+	// ZeroCopyMeshes makes the vertex data of the meshes point straight into
+	// the imported C data instead of copying it, and returns the faces as a
+	// flat index buffer (Mesh.Indices and Mesh.FaceOffsets). The data is
+	// valid until the scene is closed; Mesh.Detach and Scene.Detach copy it
+	// into Go memory.
+	ZeroCopyMeshes bool
+
+	// Parallelism is the number of goroutines that convert meshes,
+	// materials and textures to Go once Assimp is done. Zero parses on the
+	// calling goroutine, a negative value uses runtime.GOMAXPROCS(0). The
+	// resulting scene is the same either way.
+	Parallelism int
+
+	// Skip leaves parts of the scene out of the Go copy. Parts Assimp can
+	// remove itself are also dropped on the C side through
+	// PostProcessRemoveComponent.
+	Skip SkipOptions
+	// MeshFilter, if set, is called with the name and material index of
+	// every mesh. Meshes it returns false for are not converted and left as
+	// nil in Scene.Meshes, so node mesh indices stay valid. It is called
+	// in mesh order on the importing goroutine, even with Parallelism set.
+	MeshFilter func(name string, materialIndex uint) bool
+
+	props []property
+}
+
+// SkipOptions selects parts of an imported scene that are not converted to
+// Go. Skipped slices are empty.
+type SkipOptions struct {
+	Textures   bool
+	Animations bool
+	Bones      bool
+	AnimMeshes bool
+	Cameras    bool
+	Lights     bool
+}
+
+// components returns the parts of k that PostProcessRemoveComponent can
+// remove. Anim meshes have no component of their own.
+func (k SkipOptions) components() Component {
+
+	var c Component
+
+	if k.Textures {
+		c |= ComponentTextures
+	}
+	if k.Animations {
+		c |= ComponentAnimations
+	}
+	if k.Bones {
+		c |= ComponentBoneWeights
+	}
+	if k.Cameras {
+		c |= ComponentCameras
+	}
+	if k.Lights {
+		c |= ComponentLights
+	}
+
+	return c
+}
+
+// Bool returns a pointer to v, for the optional boolean ImportOptions fields.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// SetInt sets an integer (or boolean, as 0/1) importer property. Raw
+// properties are applied after the typed fields and win on conflict.
+func (o *ImportOptions) SetInt(name string, v int) {
+	o.props = append(o.props, property{name: name, kind: propertyInt, i: v})
+}
+
+// SetFloat sets a floating point importer property.
+func (o *ImportOptions) SetFloat(name string, v float32) {
+	o.props = append(o.props, property{name: name, kind: propertyFloat, f: v})
+}
+
+// SetString sets a string importer property.
+func (o *ImportOptions) SetString(name string, v string) {
+	o.props = append(o.props, property{name: name, kind: propertyString, s: v})
+}
+
+// SetMatrix sets a matrix importer property, e.g. PP_PTV_ROOT_TRANSFORMATION.
+// m uses the same convention as Node.Transformation.
+func (o *ImportOptions) SetMatrix(name string, m mat4.T) {
+	o.props = append(o.props, property{name: name, kind: propertyMatrix, m: m})
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// properties flattens the typed fields and raw setters into the list of
+// properties handed to Assimp.
+func (o *ImportOptions) properties() []property {
+
+	var props []property
+
+	setInt := func(name string, v int) {
+		props = append(props, property{name: name, kind: propertyInt, i: v})
+	}
+	setFloat := func(name string, v float32) {
+		props = append(props, property{name: name, kind: propertyFloat, f: v})
+	}
+
+	if o.SmoothingAngle != 0 {
+		setFloat(ConfigSmoothingAngle, o.SmoothingAngle)
+	}
+	if o.TangentSmoothingAngle != 0 {
+		setFloat(ConfigTangentSmoothingAngle, o.TangentSmoothingAngle)
+	}
+	if o.TangentUVChannel != 0 {
+		setInt(ConfigTangentUVChannel, o.TangentUVChannel)
+	}
+	if c := o.RemoveComponents | o.Skip.components(); c != 0 {
+		setInt(ConfigRemoveComponents, int(c))
+	}
+	if o.RemovePrimitiveTypes != 0 {
+		setInt(ConfigRemovePrimitiveTypes, int(o.RemovePrimitiveTypes))
+	}
+	if o.RemoveDegenerates {
+		setInt(ConfigRemoveDegenerates, 1)
+	}
+	if o.SplitLargeMeshTriangles != 0 {
+		setInt(ConfigSplitLargeMeshTriangles, o.SplitLargeMeshTriangles)
+	}
+	if o.SplitLargeMeshVertices != 0 {
+		setInt(ConfigSplitLargeMeshVertices, o.SplitLargeMeshVertices)
+	}
+	if o.MaxBoneWeights != 0 {
+		setInt(ConfigMaxBoneWeights, o.MaxBoneWeights)
+	}
+	if o.MaxBonesPerMesh != 0 {
+		setInt(ConfigMaxBonesPerMesh, o.MaxBonesPerMesh)
+	}
+	if o.CacheLocalitySize != 0 {
+		setInt(ConfigCacheLocalitySize, o.CacheLocalitySize)
+	}
+	if o.GlobalScale != 0 {
+		setFloat(ConfigGlobalScale, o.GlobalScale)
+	}
+	if o.NoSkeletonMeshes {
+		setInt(ConfigNoSkeletonMeshes, 1)
+	}
+	if o.FBXPreservePivots != nil {
+		setInt(ConfigFBXPreservePivots, boolToInt(*o.FBXPreservePivots))
+	}
+	if o.FBXReadAnimations != nil {
+		setInt(ConfigFBXReadAnimations, boolToInt(*o.FBXReadAnimations))
+	}
+	if o.FBXStrictMode {
+		setInt(ConfigFBXStrictMode, 1)
+	}
+	if o.FBXConvertToMeters {
+		setInt(ConfigFBXConvertToMeters, 1)
+	}
+	if o.ColladaIgnoreUpDirection {
+		setInt(ConfigColladaIgnoreUpDirection, 1)
+	}
+
+	return append(props, o.props...)
+}
+
+// newPropertyStore creates an aiPropertyStore holding the options. A nil
+// store is returned for nil options, which Assimp treats as "all defaults".
+func newPropertyStore(o *ImportOptions) (*C.struct_aiPropertyStore, func(), error) {
+
+	if o == nil {
+		return nil, func() {}, nil
+	}
+
+	store := C.aiCreatePropertyStore()
+	release := func() { C.aiReleasePropertyStore(store) }
+
+	for _, p := range o.properties() {
+
+		cname := C.CString(p.name)
+
+		switch p.kind {
+		case propertyInt:
+			C.aiSetImportPropertyInteger(store, cname, C.int(p.i))
+		case propertyFloat:
+			C.aiSetImportPropertyFloat(store, cname, C.ai_real(p.f))
+		case propertyString:
+			if len(p.s) >= C.MAXLEN {
+				C.free(unsafe.Pointer(cname))
+				release()
+				return nil, func() {}, errors.New("asig error: property " + p.name + " exceeds maximum string length")
+			}
+			cs := toAiString(p.s)
+			C.aiSetImportPropertyString(store, cname, &cs)
+		case propertyMatrix:
+			cm := toAiMatrix(&p.m)
+			C.aiSetImportPropertyMatrix(store, cname, &cm)
+		}
+
+		C.free(unsafe.Pointer(cname))
+	}
+
+	return store, release, nil
+}
+
+func toAiString(s string) C.struct_aiString {
+
+	var cs C.struct_aiString
+	cs.length = C.uint(len(s))
+
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&cs.data[0])), len(cs.data))
+	copy(data, s)
+
+	return cs
+}
+
+// toAiMatrix is the inverse of parseMat4.
+func toAiMatrix(m *mat4.T) C.struct_aiMatrix4x4 {
+	return C.struct_aiMatrix4x4{
+		a1: C.ai_real(m[0][0]), b1: C.ai_real(m[0][1]), c1: C.ai_real(m[0][2]), d1: C.ai_real(m[0][3]),
+		a2: C.ai_real(m[1][0]), b2: C.ai_real(m[1][1]), c2: C.ai_real(m[1][2]), d2: C.ai_real(m[1][3]),
+		a3: C.ai_real(m[2][0]), b3: C.ai_real(m[2][1]), c3: C.ai_real(m[2][2]), d3: C.ai_real(m[2][3]),
+		a4: C.ai_real(m[3][0]), b4: C.ai_real(m[3][1]), c4: C.ai_real(m[3][2]), d4: C.ai_real(m[3][3]),
+	}
+}
+
+// ImportFileWithOptions is ImportFile with importer properties. opts may be nil.
+func ImportFileWithOptions(file string, postProcessFlags PostProcess, opts *ImportOptions) (s *Scene, release func(), err error) {
+
+	store, releaseStore, err := newPropertyStore(opts)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	defer releaseStore()
+
+	cstr := C.CString(file)
+	defer C.free(unsafe.Pointer(cstr))
+
+	if opts != nil && opts.Skip.components() != 0 {
+		postProcessFlags |= PostProcessRemoveComponent
+	}
+
+	cs, session := importLocked(opts, func() *C.struct_aiScene {
+		return C.aiImportFileExWithProperties(cstr, C.uint(postProcessFlags), nil, store)
+	})
+	if cs == nil {
+		return nil, func() {}, newImportError(file, "", session.errString)
+	}
+
+	s = newImportedScene(cs, session, opts, postProcessFlags)
+	return s, func() { s.Close() }, nil
+}
@@ -0,0 +1,165 @@
+package assimp
+
+import (
+	"testing"
+
+	"github.com/flywave/go3d/mat4"
+)
+
+// TestImportOptionsDefaults 测试默认选项不产生任何属性
+func TestImportOptionsDefaults(t *testing.T) {
+	opts := &ImportOptions{}
+
+	if props := opts.properties(); len(props) != 0 {
+		t.Errorf("Expected no properties for zero options, got %d", len(props))
+	}
+}
+
+// TestImportOptionsProperties 测试类型化字段和原始属性的转换
+func TestImportOptionsProperties(t *testing.T) {
+	opts := &ImportOptions{
+		SmoothingAngle:    80,
+		RemoveComponents:  ComponentColors | ComponentTexCoordsN(1),
+		GlobalScale:       0.01,
+		FBXPreservePivots: Bool(false),
+	}
+	opts.SetInt(ConfigMaxBoneWeights, 8)
+	opts.SetString("IMPORT_MD3_SKIN_NAME", "default")
+	opts.SetMatrix("PP_PTV_ROOT_TRANSFORMATION", mat4.Ident)
+
+	props := opts.properties()
+
+	byName := map[string]property{}
+	for _, p := range props {
+		byName[p.name] = p
+	}
+
+	if p, ok := byName[ConfigSmoothingAngle]; !ok || p.kind != propertyFloat || p.f != 80 {
+		t.Errorf("Expected smoothing angle float property 80, got %+v", p)
+	}
+
+	if p, ok := byName[ConfigRemoveComponents]; !ok || p.kind != propertyInt || p.i != int(ComponentColors|1<<26) {
+		t.Errorf("Expected remove components int property, got %+v", p)
+	}
+
+	if p, ok := byName[ConfigGlobalScale]; !ok || p.f != 0.01 {
+		t.Errorf("Expected global scale 0.01, got %+v", p)
+	}
+
+	if p, ok := byName[ConfigFBXPreservePivots]; !ok || p.i != 0 {
+		t.Errorf("Expected preserve pivots disabled, got %+v", p)
+	}
+
+	if p, ok := byName[ConfigMaxBoneWeights]; !ok || p.i != 8 {
+		t.Errorf("Expected raw max bone weights 8, got %+v", p)
+	}
+
+	if p, ok := byName["IMPORT_MD3_SKIN_NAME"]; !ok || p.kind != propertyString || p.s != "default" {
+		t.Errorf("Expected raw string property, got %+v", p)
+	}
+
+	// 原始属性在类型化字段之后应用
+	if props[len(props)-1].kind != propertyMatrix {
+		t.Error("Expected raw properties to be applied last")
+	}
+}
+
+// TestAiMatrixRoundTrip 测试矩阵转换与parseMat4互逆
+func TestAiMatrixRoundTrip(t *testing.T) {
+	m := mat4.T{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+		{13, 14, 15, 16},
+	}
+
+	cm := toAiMatrix(&m)
+	if back := parseMat4(&cm); *back != m {
+		t.Errorf("Expected round trip to preserve matrix, got %v", *back)
+	}
+}
+
+// TestImportFileWithOptions 测试带选项导入的错误处理
+func TestImportFileWithOptions(t *testing.T) {
+	_, _, err := ImportFileWithOptions("nonexistent.obj", PostProcessTriangulate, &ImportOptions{SmoothingAngle: 30})
+	if err == nil {
+		t.Error("Expected error for nonexistent file, got nil")
+	}
+
+	_, _, err = ImportFileWithOptions("nonexistent.obj", PostProcessTriangulate, nil)
+	if err == nil {
+		t.Error("Expected error for nonexistent file with nil options, got nil")
+	}
+}
+
+// TestSkipOptionsComponents 测试Skip选项映射到RemoveComponent标志
+func TestSkipOptionsComponents(t *testing.T) {
+	if (SkipOptions{}).components() != 0 {
+		t.Error("Expected no components for the zero SkipOptions")
+	}
+
+	// 动画网格没有对应的组件
+	if (SkipOptions{AnimMeshes: true}).components() != 0 {
+		t.Error("Expected anim meshes to be skipped on the Go side only")
+	}
+
+	skip := SkipOptions{Textures: true, Animations: true, Bones: true, Cameras: true, Lights: true}
+	want := ComponentTextures | ComponentAnimations | ComponentBoneWeights | ComponentCameras | ComponentLights
+	if got := skip.components(); got != want {
+		t.Errorf("Expected components %#x, got %#x", want, got)
+	}
+
+	opts := &ImportOptions{
+		RemoveComponents: ComponentColors,
+		Skip:             SkipOptions{Lights: true},
+	}
+
+	for _, p := range opts.properties() {
+		if p.name == ConfigRemoveComponents {
+			if p.i != int(ComponentColors|ComponentLights) {
+				t.Errorf("Expected skipped components merged into %s, got %#x", ConfigRemoveComponents, p.i)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected a %s property", ConfigRemoveComponents)
+}
+
+// TestImportMeshFilter 测试被过滤的网格在Meshes中保留为nil
+func TestImportMeshFilter(t *testing.T) {
+	path := writeManyMeshesOBJ(t, 10)
+
+	var seen int
+	scene, release, err := ImportFileWithOptions(path, PostProcessTriangulate, &ImportOptions{
+		MeshFilter: func(name string, materialIndex uint) bool {
+			seen++
+			return name == "obj3"
+		},
+		Skip: SkipOptions{Textures: true, Animations: true},
+		// 过滤函数在导入的goroutine上串行调用, seen无需同步
+		Parallelism: 4,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer release()
+
+	if seen != len(scene.Meshes) {
+		t.Errorf("Expected the filter to see all %d meshes, saw %d", len(scene.Meshes), seen)
+	}
+
+	var kept []*Mesh
+	for _, m := range scene.Meshes {
+		if m != nil {
+			kept = append(kept, m)
+		}
+	}
+
+	if len(kept) != 1 || kept[0].Name != "obj3" || len(kept[0].Vertices) == 0 {
+		t.Fatalf("Expected only obj3 to be converted, got %d meshes", len(kept))
+	}
+
+	if len(scene.Textures) != 0 || len(scene.Animations) != 0 {
+		t.Error("Expected skipped parts to be empty")
+	}
+}
@@ -0,0 +1,501 @@
+package assimp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// Semantic names the mesh data a vertex attribute is filled from.
+type Semantic int
+
+const (
+	SemanticPosition Semantic = iota
+	SemanticNormal
+	// SemanticTangent packs Mesh.Tangents. A fourth component holds the
+	// handedness of the tangent frame, -1 if the bitangent is mirrored and
+	// 1 otherwise, as glTF expects.
+	SemanticTangent
+	SemanticTexCoord0
+	SemanticTexCoord1
+	SemanticTexCoord2
+	SemanticTexCoord3
+	SemanticTexCoord4
+	SemanticTexCoord5
+	SemanticTexCoord6
+	SemanticTexCoord7
+	SemanticColor0
+	SemanticColor1
+	SemanticColor2
+	SemanticColor3
+	SemanticColor4
+	SemanticColor5
+	SemanticColor6
+	SemanticColor7
+	// SemanticJoints and SemanticWeights pack the four strongest bone
+	// influences of each vertex, with the weights scaled to sum to one.
+	// Joints are indices into Mesh.Bones.
+	SemanticJoints
+	SemanticWeights
+)
+
+// TexCoordSemantic returns the semantic of texture coordinate channel i.
+func TexCoordSemantic(i int) Semantic {
+	return SemanticTexCoord0 + Semantic(i)
+}
+
+// ColorSemantic returns the semantic of vertex color set i.
+func ColorSemantic(i int) Semantic {
+	return SemanticColor0 + Semantic(i)
+}
+
+func (s Semantic) String() string {
+
+	switch {
+	case s == SemanticPosition:
+		return "Position"
+	case s == SemanticNormal:
+		return "Normal"
+	case s == SemanticTangent:
+		return "Tangent"
+	case s >= SemanticTexCoord0 && s <= SemanticTexCoord7:
+		return fmt.Sprintf("TexCoord%d", s-SemanticTexCoord0)
+	case s >= SemanticColor0 && s <= SemanticColor7:
+		return fmt.Sprintf("Color%d", s-SemanticColor0)
+	case s == SemanticJoints:
+		return "Joints"
+	case s == SemanticWeights:
+		return "Weights"
+	default:
+		return "Unknown"
+	}
+}
+
+// ComponentType is the storage type of one component of a vertex
+// attribute. The normalized integer types map [-1,1] or [0,1] onto their
+// full range, the plain integer types are meant for joint indices.
+type ComponentType int
+
+const (
+	ComponentFloat32 ComponentType = iota
+	ComponentFloat16
+	ComponentInt8Norm
+	ComponentUint8Norm
+	ComponentInt16Norm
+	ComponentUint16Norm
+	ComponentUint8
+	ComponentUint16
+)
+
+// Size returns the size of one component in bytes.
+func (ct ComponentType) Size() int {
+
+	switch ct {
+	case ComponentFloat32:
+		return 4
+	case ComponentFloat16, ComponentInt16Norm, ComponentUint16Norm, ComponentUint16:
+		return 2
+	case ComponentInt8Norm, ComponentUint8Norm, ComponentUint8:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (ct ComponentType) String() string {
+
+	switch ct {
+	case ComponentFloat32:
+		return "Float32"
+	case ComponentFloat16:
+		return "Float16"
+	case ComponentInt8Norm:
+		return "Int8Norm"
+	case ComponentUint8Norm:
+		return "Uint8Norm"
+	case ComponentInt16Norm:
+		return "Int16Norm"
+	case ComponentUint16Norm:
+		return "Uint16Norm"
+	case ComponentUint8:
+		return "Uint8"
+	case ComponentUint16:
+		return "Uint16"
+	default:
+		return "Unknown"
+	}
+}
+
+// VertexAttribute describes one attribute of an interleaved vertex.
+type VertexAttribute struct {
+	Semantic Semantic
+	Type     ComponentType
+	// Components is the number of components, 1 to 4. Missing source
+	// components are filled with 0, except the w of a position, which is 1.
+	Components int
+}
+
+// VertexLayout is the ordered list of attributes of an interleaved vertex.
+type VertexLayout []VertexAttribute
+
+// Offsets returns the byte offset of each attribute within a vertex and
+// the vertex stride. Attributes start on 4 byte boundaries, as WebGL and
+// most Vulkan implementations require.
+func (l VertexLayout) Offsets() (offsets []int, stride int) {
+
+	offsets = make([]int, len(l))
+	for i, a := range l {
+		offsets[i] = stride
+		stride += align4(a.Type.Size() * a.Components)
+	}
+
+	return offsets, stride
+}
+
+// Validate checks that the layout can be packed.
+func (l VertexLayout) Validate() error {
+
+	if len(l) == 0 {
+		return errors.New("assimp: empty vertex layout")
+	}
+
+	seen := make(map[Semantic]bool, len(l))
+	for _, a := range l {
+		if a.Semantic < SemanticPosition || a.Semantic > SemanticWeights {
+			return fmt.Errorf("assimp: invalid vertex semantic %d", a.Semantic)
+		}
+		if seen[a.Semantic] {
+			return fmt.Errorf("assimp: duplicate vertex attribute %v", a.Semantic)
+		}
+		seen[a.Semantic] = true
+
+		if a.Type.Size() == 0 {
+			return fmt.Errorf("assimp: invalid component type %d for %v", a.Type, a.Semantic)
+		}
+		if a.Components < 1 || a.Components > 4 {
+			return fmt.Errorf("assimp: %v has %d components, want 1 to 4", a.Semantic, a.Components)
+		}
+
+		integer := a.Type == ComponentUint8 || a.Type == ComponentUint16
+		if a.Semantic == SemanticJoints && !integer && a.Type != ComponentFloat32 && a.Type != ComponentFloat16 {
+			return fmt.Errorf("assimp: joints can't be stored as %v", a.Type)
+		}
+		if a.Semantic != SemanticJoints && integer {
+			return fmt.Errorf("assimp: %v can't be stored as %v, use a normalized type", a.Semantic, a.Type)
+		}
+	}
+
+	return nil
+}
+
+// IndexFormat is the type of the entries of an index buffer.
+type IndexFormat int
+
+const (
+	IndexUint16 IndexFormat = iota
+	IndexUint32
+)
+
+// Size returns the size of one index in bytes.
+func (f IndexFormat) Size() int {
+	if f == IndexUint16 {
+		return 2
+	}
+	return 4
+}
+
+func (f IndexFormat) String() string {
+	if f == IndexUint16 {
+		return "Uint16"
+	}
+	return "Uint32"
+}
+
+// PackedMesh is a mesh packed into GPU ready buffers. All values are
+// stored little-endian.
+type PackedMesh struct {
+	Layout VertexLayout
+	// Vertices holds VertexCount interleaved vertices of Stride bytes, the
+	// attribute i of each starting at Offsets[i].
+	Vertices    []byte
+	Stride      int
+	Offsets     []int
+	VertexCount int
+
+	// Indices holds IndexCount indices of type IndexFormat, which is
+	// IndexUint16 whenever all vertices can be addressed with it.
+	Indices     []byte
+	IndexFormat IndexFormat
+	IndexCount  int
+	// Primitive is the primitive the indices draw: triangles if the mesh
+	// has any, polygons being triangulated as fans, else lines, else
+	// points. Faces of other primitive types are left out.
+	Primitive PrimitiveType
+}
+
+// Pack packs the mesh into an interleaved vertex buffer laid out as layout,
+// and a matching index buffer. It fails if the layout is invalid or the
+// mesh is missing data an attribute needs.
+func (m *Mesh) Pack(layout VertexLayout) (*PackedMesh, error) {
+
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := len(m.Vertices)
+	if n == 0 {
+		return nil, fmt.Errorf("assimp: mesh %q has no vertices", m.Name)
+	}
+
+	offsets, stride := layout.Offsets()
+	pm := &PackedMesh{
+		Layout:      layout,
+		Vertices:    make([]byte, n*stride),
+		Stride:      stride,
+		Offsets:     offsets,
+		VertexCount: n,
+	}
+
+	for i, a := range layout {
+		src, err := m.attributeSource(a)
+		if err != nil {
+			return nil, err
+		}
+
+		size := a.Type.Size()
+		for v := range n {
+			values := src(v)
+			dst := pm.Vertices[v*stride+offsets[i]:]
+			for c := range a.Components {
+				putComponent(dst[c*size:], a.Type, values[c])
+			}
+		}
+	}
+
+	m.packIndices(pm)
+
+	return pm, nil
+}
+
+// attributeSource returns a function that gives the four source components
+// of attribute a for a vertex.
+func (m *Mesh) attributeSource(a VertexAttribute) (func(v int) [4]float32, error) {
+
+	n := len(m.Vertices)
+	missing := func() error {
+		return fmt.Errorf("assimp: mesh %q has no %v data", m.Name, a.Semantic)
+	}
+
+	vec3Source := func(s []vec3.T, w float32) (func(v int) [4]float32, error) {
+		if len(s) < n {
+			return nil, missing()
+		}
+		return func(v int) [4]float32 {
+			return [4]float32{s[v][0], s[v][1], s[v][2], w}
+		}, nil
+	}
+
+	switch {
+	case a.Semantic == SemanticPosition:
+		return vec3Source(m.Vertices, 1)
+
+	case a.Semantic == SemanticNormal:
+		return vec3Source(m.Normals, 0)
+
+	case a.Semantic == SemanticTangent:
+		if len(m.Tangents) < n {
+			return nil, missing()
+		}
+		frame := len(m.Normals) >= n && len(m.BitTangents) >= n
+		return func(v int) [4]float32 {
+			t := m.Tangents[v]
+			sign := float32(1)
+			if frame {
+				sign = tangentSign(m.Normals[v], t, m.BitTangents[v])
+			}
+			return [4]float32{t[0], t[1], t[2], sign}
+		}, nil
+
+	case a.Semantic >= SemanticTexCoord0 && a.Semantic <= SemanticTexCoord7:
+		return vec3Source(m.TexCoords[a.Semantic-SemanticTexCoord0], 0)
+
+	case a.Semantic >= SemanticColor0 && a.Semantic <= SemanticColor7:
+		colors := m.ColorSets[a.Semantic-SemanticColor0]
+		if len(colors) < n {
+			return nil, missing()
+		}
+		return func(v int) [4]float32 {
+			return [4]float32(colors[v])
+		}, nil
+
+	case a.Semantic == SemanticJoints || a.Semantic == SemanticWeights:
+		if len(m.Bones) == 0 {
+			return nil, missing()
+		}
+		if a.Type == ComponentUint8 && len(m.Bones) > math.MaxUint8+1 ||
+			a.Type == ComponentUint16 && len(m.Bones) > math.MaxUint16+1 {
+			return nil, fmt.Errorf("assimp: mesh %q has %d bones, too many for %v joints", m.Name, len(m.Bones), a.Type)
+		}
+		joints, weights := m.skinInfluences()
+		if a.Semantic == SemanticJoints {
+			return func(v int) [4]float32 { return joints[v] }, nil
+		}
+		return func(v int) [4]float32 { return weights[v] }, nil
+	}
+
+	return nil, fmt.Errorf("assimp: invalid vertex semantic %d", a.Semantic)
+}
+
+// skinInfluences returns the four strongest bone influences of each vertex,
+// with the weights scaled to sum to one.
+func (m *Mesh) skinInfluences() (joints, weights [][4]float32) {
+
+	n := len(m.Vertices)
+	joints = make([][4]float32, n)
+	weights = make([][4]float32, n)
+
+	for b, bone := range m.Bones {
+		for _, w := range bone.Weights {
+			v := int(w.VertIndex)
+			if v >= n || w.Weight <= weights[v][3] {
+				continue
+			}
+
+			// Insert sorted by descending weight.
+			i := 3
+			for i > 0 && weights[v][i-1] < w.Weight {
+				weights[v][i] = weights[v][i-1]
+				joints[v][i] = joints[v][i-1]
+				i--
+			}
+			weights[v][i] = w.Weight
+			joints[v][i] = float32(b)
+		}
+	}
+
+	for v := range weights {
+		sum := weights[v][0] + weights[v][1] + weights[v][2] + weights[v][3]
+		if sum > 0 {
+			for i := range weights[v] {
+				weights[v][i] /= sum
+			}
+		}
+	}
+
+	return joints, weights
+}
+
+// packIndices fills the index buffer of pm from the faces of the mesh.
+func (m *Mesh) packIndices(pm *PackedMesh) {
+
+	var indices []uint32
+	switch pt := m.primitiveTypes(); {
+	case pt&(PrimitiveTypeTriangle|PrimitiveTypePolygon) != 0:
+		pm.Primitive = PrimitiveTypeTriangle
+		for _, tri := range m.Triangles() {
+			indices = append(indices, tri[:]...)
+		}
+	case pt&PrimitiveTypeLine != 0:
+		pm.Primitive = PrimitiveTypeLine
+		for _, line := range m.Lines() {
+			indices = append(indices, line[:]...)
+		}
+	case pt&PrimitiveTypePoint != 0:
+		pm.Primitive = PrimitiveTypePoint
+		indices = m.Points()
+	}
+
+	pm.IndexCount = len(indices)
+	if pm.VertexCount <= math.MaxUint16 {
+		pm.IndexFormat = IndexUint16
+		pm.Indices = make([]byte, 2*len(indices))
+		for i, idx := range indices {
+			binary.LittleEndian.PutUint16(pm.Indices[2*i:], uint16(idx))
+		}
+	} else {
+		pm.IndexFormat = IndexUint32
+		pm.Indices = make([]byte, 4*len(indices))
+		for i, idx := range indices {
+			binary.LittleEndian.PutUint32(pm.Indices[4*i:], idx)
+		}
+	}
+}
+
+// putComponent stores v at the start of dst as type ct.
+func putComponent(dst []byte, ct ComponentType, v float32) {
+
+	switch ct {
+	case ComponentFloat32:
+		binary.LittleEndian.PutUint32(dst, math.Float32bits(v))
+	case ComponentFloat16:
+		binary.LittleEndian.PutUint16(dst, float16Bits(v))
+	case ComponentInt8Norm:
+		dst[0] = byte(int8(unitToInt(v, -1, math.MaxInt8)))
+	case ComponentUint8Norm:
+		dst[0] = uint8(unitToInt(v, 0, math.MaxUint8))
+	case ComponentInt16Norm:
+		binary.LittleEndian.PutUint16(dst, uint16(int16(unitToInt(v, -1, math.MaxInt16))))
+	case ComponentUint16Norm:
+		binary.LittleEndian.PutUint16(dst, uint16(unitToInt(v, 0, math.MaxUint16)))
+	case ComponentUint8:
+		dst[0] = uint8(min(max(math.Round(float64(v)), 0), math.MaxUint8))
+	case ComponentUint16:
+		binary.LittleEndian.PutUint16(dst, uint16(min(max(math.Round(float64(v)), 0), math.MaxUint16)))
+	}
+}
+
+// unitToInt converts v, clamped to [lo,1], to an integer with 1 mapped to
+// scale, rounding to the nearest value.
+func unitToInt(v float32, lo, scale float64) int32 {
+	f := min(max(float64(v), lo), 1)
+	return int32(math.Round(f * scale))
+}
+
+// float16Bits returns the IEEE 754 half precision encoding of f, rounding
+// to the nearest value, ties to even.
+func float16Bits(f float32) uint16 {
+
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int32(b>>23&0xff) - 127 + 15
+	mant := b & 0x7fffff
+
+	switch {
+	case b&0x7fffffff > 0x7f800000:
+		// NaN
+		return sign | 0x7e00
+	case exp >= 0x1f:
+		// Overflow to infinity.
+		return sign | 0x7c00
+	case exp <= 0:
+		// Subnormal or zero.
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := mant >> shift
+		rem := mant & (1<<shift - 1)
+		mid := uint32(1) << (shift - 1)
+		if rem > mid || rem == mid && half&1 == 1 {
+			half++
+		}
+		return sign | uint16(half)
+	}
+
+	// A carry from rounding may reach the exponent, which is still correct.
+	half := uint32(exp)<<10 | mant>>13
+	rem := mant & 0x1fff
+	if rem > 0x1000 || rem == 0x1000 && half&1 == 1 {
+		half++
+	}
+
+	return sign | uint16(half)
+}
+
+func align4(n int) int {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,206 @@
+package assimp
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+	"github.com/flywave/go3d/vec4"
+)
+
+// TestMeshPack 测试交错顶点缓冲的布局和各分量类型的编码
+func TestMeshPack(t *testing.T) {
+	mesh := triangleMesh("tri", 0)
+	mesh.Tangents = []vec3.T{{1, 0, 0}, {1, 0, 0}, {1, 0, 0}}
+	// 顶点2的副切线是镜像的
+	mesh.BitTangents = []vec3.T{{0, 1, 0}, {0, 1, 0}, {0, -1, 0}}
+	mesh.TexCoords[1] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {0.5, -2, 0}}
+	mesh.ColorSets[0] = []vec4.T{{1, 0.5, 0, 1}, {0, 0, 0, 1}, {2, -1, 0, 0}}
+
+	layout := VertexLayout{
+		{Semantic: SemanticPosition, Type: ComponentFloat32, Components: 3},
+		{Semantic: SemanticNormal, Type: ComponentInt8Norm, Components: 3},
+		{Semantic: SemanticTangent, Type: ComponentInt16Norm, Components: 4},
+		{Semantic: TexCoordSemantic(1), Type: ComponentFloat16, Components: 2},
+		{Semantic: SemanticColor0, Type: ComponentUint8Norm, Components: 4},
+	}
+
+	pm, err := mesh.Pack(layout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 3个int8分量对齐到4字节
+	wantOffsets := []int{0, 12, 16, 24, 28}
+	for i, off := range wantOffsets {
+		if pm.Offsets[i] != off {
+			t.Errorf("Expected offsets %v, got %v", wantOffsets, pm.Offsets)
+			break
+		}
+	}
+	if pm.Stride != 32 || len(pm.Vertices) != 3*32 || pm.VertexCount != 3 {
+		t.Fatalf("Expected 3 vertices of 32 bytes, got stride %d and %d bytes", pm.Stride, len(pm.Vertices))
+	}
+
+	vertex := func(i int) []byte { return pm.Vertices[i*pm.Stride:] }
+
+	if x := math.Float32frombits(binary.LittleEndian.Uint32(vertex(1))); x != 1 {
+		t.Errorf("Expected position x 1, got %v", x)
+	}
+	if n := vertex(0)[12:15]; n[0] != 0 || n[1] != 0 || int8(n[2]) != 127 {
+		t.Errorf("Expected normal (0,0,127), got %v", n)
+	}
+	if w := int16(binary.LittleEndian.Uint16(vertex(0)[22:])); w != math.MaxInt16 {
+		t.Errorf("Expected tangent sign 1 for vertex 0, got %v", w)
+	}
+	if w := int16(binary.LittleEndian.Uint16(vertex(2)[22:])); w != -math.MaxInt16 {
+		t.Errorf("Expected tangent sign -1 for the mirrored vertex, got %v", w)
+	}
+	if u, v := binary.LittleEndian.Uint16(vertex(2)[24:]), binary.LittleEndian.Uint16(vertex(2)[26:]); u != 0x3800 || v != 0xc000 {
+		t.Errorf("Expected half floats 0.5 and -2, got %#x %#x", u, v)
+	}
+	if c := vertex(0)[28:32]; c[0] != 255 || c[1] != 128 || c[2] != 0 || c[3] != 255 {
+		t.Errorf("Expected color (255,128,0,255), got %v", c)
+	}
+	if c := vertex(2)[28:30]; c[0] != 255 || c[1] != 0 {
+		t.Errorf("Expected color to be clamped, got %v", c)
+	}
+
+	if pm.IndexFormat != IndexUint16 || pm.IndexCount != 3 || pm.Primitive != PrimitiveTypeTriangle {
+		t.Errorf("Expected 3 uint16 triangle indices, got %d %v %v", pm.IndexCount, pm.IndexFormat, pm.Primitive)
+	}
+	if i := binary.LittleEndian.Uint16(pm.Indices[4:]); i != 2 {
+		t.Errorf("Expected third index 2, got %d", i)
+	}
+}
+
+// TestMeshPackSkin 测试骨骼索引和权重的打包
+func TestMeshPackSkin(t *testing.T) {
+	mesh := triangleMesh("skinned", 0)
+	for i, w := range []float32{0.1, 0.4, 0.2, 0.6, 0.7} {
+		mesh.Bones = append(mesh.Bones, &Bone{Weights: []VertexWeight{{VertIndex: 0, Weight: w}}})
+		if i == 0 {
+			mesh.Bones[0].Weights = append(mesh.Bones[0].Weights, VertexWeight{VertIndex: 1, Weight: 1})
+		}
+	}
+
+	pm, err := mesh.Pack(VertexLayout{
+		{Semantic: SemanticJoints, Type: ComponentUint8, Components: 4},
+		{Semantic: SemanticWeights, Type: ComponentFloat32, Components: 4},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 顶点0保留权重最大的4个骨骼: 4, 3, 1, 2
+	if j := pm.Vertices[0:4]; j[0] != 4 || j[1] != 3 || j[2] != 1 || j[3] != 2 {
+		t.Errorf("Expected joints (4,3,1,2), got %v", j)
+	}
+	var sum float32
+	for c := range 4 {
+		sum += math.Float32frombits(binary.LittleEndian.Uint32(pm.Vertices[4+4*c:]))
+	}
+	if math.Abs(float64(sum-1)) > 1e-6 {
+		t.Errorf("Expected weights to sum to 1, got %v", sum)
+	}
+	if w := math.Float32frombits(binary.LittleEndian.Uint32(pm.Vertices[4:])); math.Abs(float64(w-0.7/1.9)) > 1e-6 {
+		t.Errorf("Expected first weight %v, got %v", 0.7/1.9, w)
+	}
+
+	if v := pm.Vertices[pm.Stride : pm.Stride+4]; v[0] != 0 || math.Float32frombits(binary.LittleEndian.Uint32(pm.Vertices[pm.Stride+4:])) != 1 {
+		t.Errorf("Expected vertex 1 to be bound to bone 0 only, got %v", v)
+	}
+
+	if _, err := triangleMesh("rigid", 0).Pack(VertexLayout{{Semantic: SemanticJoints, Type: ComponentUint8, Components: 4}}); err == nil {
+		t.Error("Expected an error for a mesh without bones")
+	}
+}
+
+// TestMeshPackIndexFormat 测试按顶点数选择索引类型
+func TestMeshPackIndexFormat(t *testing.T) {
+	layout := VertexLayout{{Semantic: SemanticPosition, Type: ComponentFloat32, Components: 3}}
+
+	mesh := &Mesh{
+		Vertices: make([]vec3.T, math.MaxUint16+2),
+		Faces:    []Face{{Indices: []uint{0, math.MaxUint16 + 1}}},
+	}
+	pm, err := mesh.Pack(layout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pm.IndexFormat != IndexUint32 || len(pm.Indices) != 8 || pm.Primitive != PrimitiveTypeLine {
+		t.Errorf("Expected two uint32 line indices, got %v with %d bytes", pm.IndexFormat, len(pm.Indices))
+	}
+	if i := binary.LittleEndian.Uint32(pm.Indices[4:]); i != math.MaxUint16+1 {
+		t.Errorf("Expected index %d, got %d", math.MaxUint16+1, i)
+	}
+
+	// 四边形按扇形三角化
+	quad := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2, 3}}},
+	}
+	if pm, _ := quad.Pack(layout); pm.IndexFormat != IndexUint16 || pm.IndexCount != 6 {
+		t.Errorf("Expected 6 uint16 indices for a quad, got %d %v", pm.IndexCount, pm.IndexFormat)
+	}
+}
+
+// TestVertexLayoutValidate 测试无效布局的检查
+func TestVertexLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout VertexLayout
+	}{
+		{"empty", VertexLayout{}},
+		{"duplicate", VertexLayout{
+			{Semantic: SemanticPosition, Type: ComponentFloat32, Components: 3},
+			{Semantic: SemanticPosition, Type: ComponentFloat16, Components: 3},
+		}},
+		{"components", VertexLayout{{Semantic: SemanticNormal, Type: ComponentFloat32, Components: 5}}},
+		{"semantic", VertexLayout{{Semantic: SemanticWeights + 1, Type: ComponentFloat32, Components: 1}}},
+		{"type", VertexLayout{{Semantic: SemanticNormal, Type: ComponentUint16 + 1, Components: 3}}},
+		{"integer normal", VertexLayout{{Semantic: SemanticNormal, Type: ComponentUint8, Components: 3}}},
+		{"normalized joints", VertexLayout{{Semantic: SemanticJoints, Type: ComponentUint8Norm, Components: 4}}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.layout.Validate(); err == nil {
+			t.Errorf("Expected an error for the %s layout", tt.name)
+		}
+	}
+
+	if _, err := triangleMesh("tri", 0).Pack(VertexLayout{{Semantic: SemanticTexCoord0, Type: ComponentFloat32, Components: 2}}); err == nil {
+		t.Error("Expected an error for a missing texture coordinate channel")
+	}
+}
+
+// TestFloat16Bits 测试半精度浮点数的编码
+func TestFloat16Bits(t *testing.T) {
+	tests := []struct {
+		f    float32
+		want uint16
+	}{
+		{0, 0x0000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.1, 0x2e66},
+		{65504, 0x7bff},
+		{1e6, 0x7c00},
+		{float32(math.Inf(-1)), 0xfc00},
+		{float32(math.Pow(2, -24)), 0x0001},
+		{float32(math.Pow(2, -26)), 0x0000},
+		{1 + float32(math.Pow(2, -11)), 0x3c00},
+		{1 + 3*float32(math.Pow(2, -11)), 0x3c02},
+	}
+
+	for _, tt := range tests {
+		if got := float16Bits(tt.f); got != tt.want {
+			t.Errorf("Expected %#04x for %v, got %#04x", tt.want, tt.f, got)
+		}
+	}
+
+	if got := float16Bits(float32(math.NaN())); got&0x7c00 != 0x7c00 || got&0x3ff == 0 {
+		t.Errorf("Expected a NaN, got %#04x", got)
+	}
+}
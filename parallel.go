@@ -0,0 +1,58 @@
+package assimp
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// workers returns the number of goroutines used to parse a scene. o may be
+// nil.
+func (o *ImportOptions) workers() int {
+	if o == nil || o.Parallelism == 0 {
+		return 1
+	}
+
+	if o.Parallelism < 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+
+	return o.Parallelism
+}
+
+// parallelFor calls fn for every index in [0, n) on up to workers
+// goroutines and returns once all calls are done. Callers store results by
+// index, so the outcome doesn't depend on scheduling.
+func parallelFor(n int, workers int, fn func(i int)) {
+
+	if workers > n {
+		workers = n
+	}
+
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
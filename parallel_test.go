@@ -0,0 +1,139 @@
+package assimp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// TestParallelFor 测试每个索引都恰好被处理一次
+func TestParallelFor(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 64} {
+		counts := make([]atomic.Int32, 50)
+
+		parallelFor(len(counts), workers, func(i int) {
+			counts[i].Add(1)
+		})
+
+		for i := range counts {
+			if n := counts[i].Load(); n != 1 {
+				t.Fatalf("workers=%d: index %d handled %d times", workers, i, n)
+			}
+		}
+	}
+
+	parallelFor(0, 4, func(i int) {
+		t.Error("Expected no calls for n=0")
+	})
+}
+
+// TestImportOptionsWorkers 测试Parallelism到工作协程数的换算
+func TestImportOptionsWorkers(t *testing.T) {
+	var nilOpts *ImportOptions
+	if nilOpts.workers() != 1 {
+		t.Error("Expected nil options to parse sequentially")
+	}
+
+	if (&ImportOptions{}).workers() != 1 {
+		t.Error("Expected zero Parallelism to parse sequentially")
+	}
+
+	if (&ImportOptions{Parallelism: 4}).workers() != 4 {
+		t.Error("Expected Parallelism 4 to use 4 workers")
+	}
+
+	if (&ImportOptions{Parallelism: -1}).workers() != runtime.GOMAXPROCS(0) {
+		t.Error("Expected negative Parallelism to use GOMAXPROCS")
+	}
+}
+
+// writeManyMeshesOBJ 写入一个包含n个独立对象(每个对象一个网格和材质)的OBJ文件
+func writeManyMeshesOBJ(tb testing.TB, n int) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+
+	var obj, mtl bytes.Buffer
+	obj.WriteString("mtllib many.mtl\n")
+	for i := 0; i < n; i++ {
+		x := float32(i % 100)
+		y := float32(i / 100)
+		fmt.Fprintf(&mtl, "newmtl m%d\nKd %g %g 0.5\n", i, x/100, y/100)
+		fmt.Fprintf(&obj, "o obj%d\nusemtl m%d\n", i, i)
+		fmt.Fprintf(&obj, "v %g %g 0\nv %g %g 0\nv %g %g 0\nv %g %g 0\n", x, y, x+1, y, x+1, y+1, x, y+1)
+		fmt.Fprintf(&obj, "vt 0 0\nvt 1 0\nvt 1 1\nvt 0 1\n")
+		b := i*4 + 1
+		fmt.Fprintf(&obj, "f %d/%d %d/%d %d/%d %d/%d\n", b, b, b+1, b+1, b+2, b+2, b+3, b+3)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "many.mtl"), mtl.Bytes(), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "many.obj")
+	if err := os.WriteFile(path, obj.Bytes(), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// TestParallelParseDeterministic 测试并行解析与顺序解析结果一致
+func TestParallelParseDeterministic(t *testing.T) {
+	path := writeManyMeshesOBJ(t, 500)
+
+	seq, releaseSeq, err := ImportFileWithOptions(path, PostProcessTriangulate, &ImportOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer releaseSeq()
+
+	par, releasePar, err := ImportFileWithOptions(path, PostProcessTriangulate, &ImportOptions{Parallelism: 8})
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer releasePar()
+
+	if len(seq.Meshes) < 500 {
+		t.Fatalf("Expected at least 500 meshes, got %d", len(seq.Meshes))
+	}
+
+	if !reflect.DeepEqual(seq.Meshes, par.Meshes) {
+		t.Error("Expected parallel and sequential meshes to be identical")
+	}
+
+	if !reflect.DeepEqual(seq.Materials, par.Materials) {
+		t.Error("Expected parallel and sequential materials to be identical")
+	}
+}
+
+// benchmarkParseManyMeshes 导入一次后通过不含步骤的ApplyPostProcessing反复重新解析场景
+func benchmarkParseManyMeshes(b *testing.B, parallelism int) {
+	path := writeManyMeshesOBJ(b, 5000)
+
+	scene, release, err := ImportFileWithOptions(path, PostProcessTriangulate, &ImportOptions{Parallelism: parallelism})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer release()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := scene.ApplyPostProcessing(0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseManyMeshesSequential(b *testing.B) {
+	benchmarkParseManyMeshes(b, 0)
+}
+
+func BenchmarkParseManyMeshesParallel(b *testing.B) {
+	benchmarkParseManyMeshes(b, -1)
+}
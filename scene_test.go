@@ -1,6 +1,12 @@
 package assimp
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/flywave/go3d/mat4"
@@ -392,3 +398,143 @@ func TestSceneBounds(t *testing.T) {
 		t.Errorf("Expected max x 1, got %f", mesh.AABB.Max[0])
 	}
 }
+
+// TestApplyPostProcessingWithoutImport 测试未导入场景的后处理
+func TestApplyPostProcessingWithoutImport(t *testing.T) {
+	scene := &Scene{}
+
+	if err := scene.ApplyPostProcessing(PostProcessTriangulate); err == nil {
+		t.Error("Expected error when post-processing a scene without imported data")
+	}
+}
+
+// TestApplyPostProcessing 测试导入后再应用后处理步骤
+func TestApplyPostProcessing(t *testing.T) {
+	scene, release, err := ImportFile(filepath.Join("testdata", "hierarchy.dae"), 0)
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer release()
+
+	if len(scene.Meshes) == 0 {
+		t.Fatal("Expected the scene to have meshes")
+	}
+	for _, m := range scene.Meshes {
+		if len(m.Normals) != 0 {
+			t.Fatalf("Expected mesh %s to have no normals before post-processing", m.Name)
+		}
+	}
+
+	if err := scene.ApplyPostProcessing(PostProcessGenNormals); err != nil {
+		t.Fatalf("Unexpected post-processing error: %v", err)
+	}
+
+	for _, m := range scene.Meshes {
+		if len(m.Normals) != len(m.Vertices) {
+			t.Errorf("Expected mesh %s to have %d normals, got %d", m.Name, len(m.Vertices), len(m.Normals))
+		}
+	}
+	if scene.FindNode("hand") == nil {
+		t.Error("Expected the node tree to be parsed again")
+	}
+}
+
+// TestApplyPostProcessingLogger 测试后处理期间日志进入导入时配置的logger
+func TestApplyPostProcessingLogger(t *testing.T) {
+	var global, scoped bytes.Buffer
+
+	SetLogger(slog.New(slog.NewTextHandler(&global, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	opts := &ImportOptions{
+		Logger: slog.New(slog.NewTextHandler(&scoped, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	scene, release, err := ImportFileWithOptions(filepath.Join("testdata", "hierarchy.dae"), 0, opts)
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	defer release()
+
+	scoped.Reset()
+	global.Reset()
+
+	if err := scene.ApplyPostProcessing(PostProcessGenNormals); err != nil {
+		t.Fatalf("Unexpected post-processing error: %v", err)
+	}
+
+	if !strings.Contains(scoped.String(), "post processing pipeline") {
+		t.Errorf("Expected the import logger to receive post-processing output, got %q", scoped.String())
+	}
+	if global.Len() != 0 {
+		t.Errorf("Expected global logger to receive nothing during post-processing, got %q", global.String())
+	}
+}
+
+// TestSceneDetach 测试Detach后场景数据依然可用且重复释放无害
+func TestSceneDetach(t *testing.T) {
+	scene := &Scene{
+		RootNode: &Node{Name: "root"},
+		Animations: []*Animation{
+			{
+				name:     "walk",
+				duration: 2,
+				channels: []*NodeAnim{
+					{name: "hip", positionKeys: []VectorKey{{time: 0}, {time: 1}}},
+				},
+			},
+		},
+		Lights:  []*Light{{name: "sun", typ: LightSource_Directional}},
+		Cameras: []*Camera{{name: "main", aspect: 1.5}},
+	}
+
+	scene.Detach()
+	scene.Detach()
+	scene.releaseCResources()
+
+	if scene.Animations[0].Channels()[0].NumPositionKeys() != 2 {
+		t.Error("Expected animation channels to survive Detach")
+	}
+
+	if scene.Lights[0].Name() != "sun" || scene.Lights[0].Type() != LightSource_Directional {
+		t.Error("Expected lights to survive Detach")
+	}
+
+	if scene.Cameras[0].Aspect() != 1.5 {
+		t.Error("Expected cameras to survive Detach")
+	}
+
+	if err := scene.ApplyPostProcessing(PostProcessTriangulate); err == nil {
+		t.Error("Expected post-processing a detached scene to fail")
+	}
+}
+
+// TestSceneClose 测试Close后场景数据依然可用、重复Close无害且后处理返回ErrSceneClosed
+func TestSceneClose(t *testing.T) {
+	scene := &Scene{
+		RootNode: &Node{Name: "root"},
+		Meshes:   []*Mesh{{Vertices: []vec3.T{{1, 2, 3}}}},
+		handle:   &sceneHandle{},
+	}
+
+	var closer io.Closer = scene
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Unexpected error on Close: %v", err)
+	}
+	if err := scene.Close(); err != nil {
+		t.Errorf("Expected double Close to be a no-op, got %v", err)
+	}
+
+	if scene.RootNode.Name != "root" || scene.Meshes[0].Vertices[0] != (vec3.T{1, 2, 3}) {
+		t.Error("Expected Go data to survive Close")
+	}
+
+	if err := scene.ApplyPostProcessing(PostProcessTriangulate); !errors.Is(err, ErrSceneClosed) {
+		t.Errorf("Expected ErrSceneClosed, got %v", err)
+	}
+
+	// 未导入的场景同样可以Close
+	if err := (&Scene{}).Close(); err != nil {
+		t.Errorf("Unexpected error closing an empty scene: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package assimp
+
+import (
+	"errors"
+	"iter"
+	"strings"
+
+	"github.com/flywave/go3d/mat4"
+)
+
+// SkipSubtree can be returned by a WalkFunc to skip the children of the node
+// it was called for. Walk itself doesn't return it.
+var SkipSubtree = errors.New("skip subtree")
+
+// WalkFunc is called by Walk for every node with its world transform, i.e.
+// the product of the transformations from the root down to the node.
+type WalkFunc func(n *Node, world mat4.T) error
+
+// MeshInstance is a mesh placed in the scene by a node.
+type MeshInstance struct {
+	Node      *Node
+	Mesh      *Mesh
+	MeshIndex uint
+	// World is the world transform of Node.
+	World mat4.T
+}
+
+// localTransform returns the transformation of n, the identity if it has none.
+func (n *Node) localTransform() *mat4.T {
+	if n.Transformation == nil {
+		return &mat4.Ident
+	}
+	return n.Transformation
+}
+
+// WorldTransform returns the transformation from the local space of n to the
+// space of the root node's parent, i.e. the product of the transformations of
+// n and its ancestors. Matrices follow the convention of
+// Node.Transformation: column vectors, translation in column 3.
+func (n *Node) WorldTransform() mat4.T {
+
+	world := *n.localTransform()
+
+	for p := n.Parent; p != nil; p = p.Parent {
+		var m mat4.T
+		m.AssignMul(p.localTransform(), &world)
+		world = m
+	}
+
+	return world
+}
+
+// Walk calls fn for n and its descendants, depth-first in Children order.
+// The world transform passed for n includes its ancestors. If fn returns
+// SkipSubtree the children of that node are skipped; any other error stops
+// the walk and is returned. The hierarchy must be acyclic, see Scene.Validate.
+func (n *Node) Walk(fn WalkFunc) error {
+
+	parentWorld := mat4.Ident
+	if n.Parent != nil {
+		parentWorld = n.Parent.WorldTransform()
+	}
+
+	return n.walk(&parentWorld, fn)
+}
+
+func (n *Node) walk(parentWorld *mat4.T, fn WalkFunc) error {
+
+	var world mat4.T
+	world.AssignMul(parentWorld, n.localTransform())
+
+	if err := fn(n, world); err != nil {
+		if err == SkipSubtree {
+			return nil
+		}
+		return err
+	}
+
+	for _, c := range n.Children {
+		if c == nil {
+			continue
+		}
+		if err := c.walk(&world, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Walk walks the node hierarchy from the root node, see Node.Walk.
+func (s *Scene) Walk(fn WalkFunc) error {
+	if s.RootNode == nil {
+		return nil
+	}
+	return s.RootNode.Walk(fn)
+}
+
+// FindNode returns the first node named name in n and its descendants,
+// depth-first, or nil.
+func (n *Node) FindNode(name string) *Node {
+
+	if n.Name == name {
+		return n
+	}
+
+	for _, c := range n.Children {
+		if c == nil {
+			continue
+		}
+		if found := c.FindNode(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// FindNode returns the first node named name, depth-first from the root, or
+// nil.
+func (s *Scene) FindNode(name string) *Node {
+	if s.RootNode == nil {
+		return nil
+	}
+	return s.RootNode.FindNode(name)
+}
+
+// NodePath returns the names of the nodes from the root down to n, joined by
+// "/", e.g. "root/arm/hand".
+func (n *Node) NodePath() string {
+
+	var names []string
+	for p := n; p != nil; p = p.Parent {
+		names = append(names, p.Name)
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return strings.Join(names, "/")
+}
+
+// FindByPath returns the node at path, a "/" separated list of node names
+// starting with the name of the root node as returned by Node.NodePath. If
+// several siblings share a name the first one is followed. Returns nil if
+// there is no such node.
+func (s *Scene) FindByPath(path string) *Node {
+
+	if s.RootNode == nil {
+		return nil
+	}
+
+	names := strings.Split(path, "/")
+	if names[0] != s.RootNode.Name {
+		return nil
+	}
+
+	n := s.RootNode
+
+next:
+	for _, name := range names[1:] {
+		for _, c := range n.Children {
+			if c != nil && c.Name == name {
+				n = c
+				continue next
+			}
+		}
+		return nil
+	}
+
+	return n
+}
+
+// MeshInstances returns an iterator over every mesh referenced by a node,
+// with the world transform of that node, in Walk order. Mesh indices out of
+// range and meshes dropped by ImportOptions.MeshFilter are skipped.
+func (s *Scene) MeshInstances() iter.Seq[MeshInstance] {
+	return func(yield func(MeshInstance) bool) {
+
+		stop := errors.New("stop")
+
+		s.Walk(func(n *Node, world mat4.T) error {
+			for _, mi := range n.MeshIndicies {
+
+				if mi >= uint(len(s.Meshes)) || s.Meshes[mi] == nil {
+					continue
+				}
+
+				if !yield(MeshInstance{Node: n, Mesh: s.Meshes[mi], MeshIndex: mi, World: world}) {
+					return stop
+				}
+			}
+			return nil
+		})
+	}
+}
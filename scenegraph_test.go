@@ -0,0 +1,182 @@
+package assimp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flywave/go3d/mat4"
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildTransformedScene 构建带变换的测试场景:
+// root(平移1,0,0) -> arm(缩放2) -> hand(平移0,1,0, 网格0), root -> legs(网格1和越界的网格5)
+func buildTransformedScene() *Scene {
+	rootT := mat4.Ident
+	rootT.SetTranslation(&vec3.T{1, 0, 0})
+	armT := mat4.Ident
+	armT.ScaleVec3(&vec3.T{2, 2, 2})
+	handT := mat4.Ident
+	handT.SetTranslation(&vec3.T{0, 1, 0})
+
+	root := &Node{Name: "root", Transformation: &rootT}
+	arm := &Node{Name: "arm", Parent: root, Transformation: &armT}
+	hand := &Node{Name: "hand", Parent: arm, Transformation: &handT, MeshIndicies: []uint{0}}
+	legs := &Node{Name: "legs", Parent: root, MeshIndicies: []uint{1, 5}}
+	arm.Children = []*Node{hand}
+	root.Children = []*Node{arm, legs}
+
+	return &Scene{
+		RootNode: root,
+		Meshes:   []*Mesh{{Name: "hand_mesh"}, {Name: "legs_mesh"}},
+	}
+}
+
+// TestWorldTransform 测试世界变换按父到子的顺序相乘
+func TestWorldTransform(t *testing.T) {
+	scene := buildTransformedScene()
+	hand := scene.FindNode("hand")
+
+	world := hand.WorldTransform()
+	if world[3] != [4]float32{1, 2, 0, 1} {
+		t.Errorf("Expected world translation (1,2,0), got %v", world[3])
+	}
+
+	p := world.MulVec3(&vec3.T{1, 0, 0})
+	if p != (vec3.T{3, 2, 0}) {
+		t.Errorf("Expected (1,0,0) in hand space at (3,2,0), got %v", p)
+	}
+
+	// 没有变换矩阵的节点视为单位矩阵
+	legs := scene.FindNode("legs")
+	if w := legs.WorldTransform(); w[3] != [4]float32{1, 0, 0, 1} {
+		t.Errorf("Expected legs to inherit the root translation, got %v", w[3])
+	}
+}
+
+// TestSceneWalk 测试遍历顺序、世界变换和跳过子树
+func TestSceneWalk(t *testing.T) {
+	scene := buildTransformedScene()
+
+	var order []string
+	err := scene.Walk(func(n *Node, world mat4.T) error {
+		order = append(order, n.Name)
+		if world != n.WorldTransform() {
+			t.Errorf("Expected walk world of %s to match WorldTransform", n.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected walk error: %v", err)
+	}
+
+	if len(order) != 4 || order[0] != "root" || order[1] != "arm" || order[2] != "hand" || order[3] != "legs" {
+		t.Errorf("Unexpected walk order %v", order)
+	}
+
+	order = nil
+	scene.Walk(func(n *Node, world mat4.T) error {
+		order = append(order, n.Name)
+		if n.Name == "arm" {
+			return SkipSubtree
+		}
+		return nil
+	})
+	if len(order) != 3 || order[2] != "legs" {
+		t.Errorf("Expected hand to be skipped, got %v", order)
+	}
+
+	stop := errors.New("stop")
+	order = nil
+	err = scene.Walk(func(n *Node, world mat4.T) error {
+		order = append(order, n.Name)
+		if n.Name == "arm" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(order) != 2 {
+		t.Errorf("Expected the walk to stop at arm, got %v, %v", err, order)
+	}
+
+	// 从子节点开始遍历时包含祖先的变换
+	scene.FindNode("arm").Walk(func(n *Node, world mat4.T) error {
+		if n.Name == "hand" && world[3] != [4]float32{1, 2, 0, 1} {
+			t.Errorf("Expected walk from arm to include the root transform, got %v", world[3])
+		}
+		return nil
+	})
+}
+
+// TestNodePaths 测试节点路径与按路径查找
+func TestNodePaths(t *testing.T) {
+	scene := buildTransformedScene()
+
+	hand := scene.FindNode("hand")
+	if hand == nil || hand.NodePath() != "root/arm/hand" {
+		t.Fatalf("Expected hand at root/arm/hand, got %v", hand)
+	}
+
+	if scene.FindByPath("root/arm/hand") != hand {
+		t.Error("Expected FindByPath to find hand")
+	}
+
+	if scene.FindByPath("root") != scene.RootNode {
+		t.Error("Expected FindByPath to find the root")
+	}
+
+	for _, path := range []string{"arm/hand", "root/legs/hand", "root/arm/hand/finger", ""} {
+		if scene.FindByPath(path) != nil {
+			t.Errorf("Expected no node at %q", path)
+		}
+	}
+
+	if scene.FindNode("missing") != nil || (&Scene{}).FindNode("root") != nil {
+		t.Error("Expected FindNode to return nil for missing nodes")
+	}
+}
+
+// TestMeshInstances 测试网格实例迭代器
+func TestMeshInstances(t *testing.T) {
+	scene := buildTransformedScene()
+
+	var instances []MeshInstance
+	for inst := range scene.MeshInstances() {
+		instances = append(instances, inst)
+	}
+
+	// 越界的网格索引被跳过
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 mesh instances, got %d", len(instances))
+	}
+
+	if instances[0].Node.Name != "hand" || instances[0].Mesh.Name != "hand_mesh" || instances[0].MeshIndex != 0 {
+		t.Errorf("Unexpected first instance %+v", instances[0])
+	}
+	if instances[0].World[3] != [4]float32{1, 2, 0, 1} {
+		t.Errorf("Expected hand world transform, got %v", instances[0].World)
+	}
+
+	if instances[1].Node.Name != "legs" || instances[1].Mesh.Name != "legs_mesh" {
+		t.Errorf("Unexpected second instance %+v", instances[1])
+	}
+
+	// 提前结束迭代
+	count := 0
+	for range scene.MeshInstances() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Error("Expected iteration to stop after break")
+	}
+
+	// 被MeshFilter过滤的网格被跳过
+	scene.Meshes[0] = nil
+	count = 0
+	for range scene.MeshInstances() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected filtered meshes to be skipped, got %d instances", count)
+	}
+}
@@ -0,0 +1,296 @@
+package assimp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strings"
+	"text/tabwriter"
+)
+
+// PrimitiveCounts counts faces by primitive type.
+type PrimitiveCounts struct {
+	Points    int `json:"points"`
+	Lines     int `json:"lines"`
+	Triangles int `json:"triangles"`
+	Polygons  int `json:"polygons"`
+}
+
+// MaterialStats describes how a material is used.
+type MaterialStats struct {
+	Index uint   `json:"index"`
+	Name  string `json:"name"`
+	// Meshes is the number of meshes using the material, Instances the
+	// number of times they are referenced by nodes.
+	Meshes    int `json:"meshes"`
+	Instances int `json:"instances"`
+	Triangles int `json:"triangles"`
+}
+
+// AnimationStats describes an animation.
+type AnimationStats struct {
+	Name           string  `json:"name"`
+	Duration       float64 `json:"duration"`
+	TicksPerSecond float64 `json:"ticksPerSecond"`
+	// Seconds is Duration in seconds, 0 if TicksPerSecond is unknown.
+	Seconds      float64 `json:"seconds"`
+	Channels     int     `json:"channels"`
+	MeshChannels int     `json:"meshChannels"`
+}
+
+// SceneStats is a summary of a scene returned by Scene.Stats. It has JSON
+// tags for encoding/json and String renders it as a table.
+type SceneStats struct {
+	Nodes int `json:"nodes"`
+	// Depth is the number of levels of the node hierarchy, 1 for a lone
+	// root node.
+	Depth int `json:"depth"`
+
+	Meshes        int `json:"meshes"`
+	MeshInstances int `json:"meshInstances"`
+	Vertices      int `json:"vertices"`
+	Faces         int `json:"faces"`
+	// Triangles is the number of triangles after triangulating polygons.
+	Triangles      int             `json:"triangles"`
+	FacesByType    PrimitiveCounts `json:"facesByType"`
+	MeshesWithType PrimitiveCounts `json:"meshesWithType"`
+
+	Materials []MaterialStats `json:"materials"`
+
+	Textures int `json:"textures"`
+	// TexelBytes is the size of the decoded embedded textures at 4 bytes
+	// per texel. Compressed textures are measured with image.DecodeConfig,
+	// so only formats the program has registered, for example by importing
+	// image/png and image/jpeg, are sized. Others count with their
+	// compressed size.
+	TexelBytes int `json:"texelBytes"`
+
+	Bones int `json:"bones"`
+	// MaxInfluences is the largest number of bones affecting one vertex.
+	MaxInfluences int `json:"maxInfluences"`
+
+	Animations []AnimationStats `json:"animations"`
+
+	// Bounds is the world space bounding box of all mesh instances, nil if
+	// there are no vertices.
+	Bounds *AABB `json:"bounds"`
+}
+
+// Stats returns a summary of the scene for inspection and QA, see
+// SceneStats. Meshes dropped by ImportOptions.MeshFilter are not counted.
+func (s *Scene) Stats() *SceneStats {
+
+	st := &SceneStats{
+		Materials:  make([]MaterialStats, len(s.Materials)),
+		Animations: make([]AnimationStats, 0, len(s.Animations)),
+	}
+
+	for i, m := range s.Materials {
+		st.Materials[i].Index = uint(i)
+		if m == nil {
+			continue
+		}
+		if p := m.findProperty(matKeyName, 0, 0); p != nil {
+			st.Materials[i].Name, _ = p.stringValue()
+		}
+	}
+
+	if s.RootNode != nil {
+		st.Depth = nodeDepth(s.RootNode, &st.Nodes)
+	}
+
+	meshTriangles := make([]int, len(s.Meshes))
+	for i, m := range s.Meshes {
+		if m != nil {
+			meshTriangles[i] = st.addMesh(m)
+		}
+	}
+
+	for inst := range s.MeshInstances() {
+
+		st.MeshInstances++
+
+		if mi := inst.Mesh.MaterialIndex; mi < uint(len(st.Materials)) {
+			st.Materials[mi].Instances++
+		}
+	}
+
+	if box, ok := s.Bounds(); ok {
+		st.Bounds = &box
+	}
+
+	for i, m := range s.Meshes {
+		if m != nil && m.MaterialIndex < uint(len(st.Materials)) {
+			st.Materials[m.MaterialIndex].Meshes++
+			st.Materials[m.MaterialIndex].Triangles += meshTriangles[i]
+		}
+	}
+
+	st.Textures = len(s.Textures)
+	for _, t := range s.Textures {
+		st.TexelBytes += texelBytes(t)
+	}
+
+	for _, a := range s.Animations {
+
+		as := AnimationStats{
+			Name:           a.Name(),
+			Duration:       a.Duration(),
+			TicksPerSecond: a.TicksPerSecond(),
+			Channels:       a.NumChannels(),
+			MeshChannels:   a.NumMeshChannels(),
+		}
+
+		if as.TicksPerSecond > 0 {
+			as.Seconds = as.Duration / as.TicksPerSecond
+		}
+
+		st.Animations = append(st.Animations, as)
+	}
+
+	return st
+}
+
+// addMesh adds the counts of m and returns its number of triangles.
+func (st *SceneStats) addMesh(m *Mesh) int {
+
+	st.Meshes++
+	st.Vertices += len(m.Vertices)
+	st.Bones += len(m.Bones)
+
+	var faces PrimitiveCounts
+	triangles := 0
+
+	count := func(n int) {
+		switch {
+		case n == 1:
+			faces.Points++
+		case n == 2:
+			faces.Lines++
+		case n == 3:
+			faces.Triangles++
+			triangles++
+		case n > 3:
+			faces.Polygons++
+			triangles += n - 2
+		}
+	}
+
+	if len(m.FaceOffsets) > 0 {
+		for i := 0; i+1 < len(m.FaceOffsets); i++ {
+			count(int(m.FaceOffsets[i+1] - m.FaceOffsets[i]))
+		}
+	} else {
+		for _, f := range m.Faces {
+			count(len(f.Indices))
+		}
+	}
+
+	st.FacesByType.Points += faces.Points
+	st.FacesByType.Lines += faces.Lines
+	st.FacesByType.Triangles += faces.Triangles
+	st.FacesByType.Polygons += faces.Polygons
+	st.Faces += faces.Points + faces.Lines + faces.Triangles + faces.Polygons
+	st.Triangles += triangles
+
+	for _, c := range []struct{ faces, meshes *int }{
+		{&faces.Points, &st.MeshesWithType.Points},
+		{&faces.Lines, &st.MeshesWithType.Lines},
+		{&faces.Triangles, &st.MeshesWithType.Triangles},
+		{&faces.Polygons, &st.MeshesWithType.Polygons},
+	} {
+		if *c.faces > 0 {
+			*c.meshes++
+		}
+	}
+
+	if len(m.Bones) > 0 {
+		influences := make([]int, len(m.Vertices))
+		for _, b := range m.Bones {
+			for _, w := range b.Weights {
+				if w.Weight > 0 && w.VertIndex < uint(len(influences)) {
+					influences[w.VertIndex]++
+				}
+			}
+		}
+		for _, n := range influences {
+			st.MaxInfluences = max(st.MaxInfluences, n)
+		}
+	}
+
+	return triangles
+}
+
+// nodeDepth returns the depth of the hierarchy below n and adds the number
+// of nodes in it to count.
+func nodeDepth(n *Node, count *int) int {
+
+	*count++
+
+	depth := 0
+	for _, c := range n.Children {
+		if c != nil {
+			depth = max(depth, nodeDepth(c, count))
+		}
+	}
+
+	return depth + 1
+}
+
+// texelBytes returns the decoded size of t, see SceneStats.TexelBytes.
+func texelBytes(t *EmbeddedTexture) int {
+
+	if t == nil {
+		return 0
+	}
+
+	if !t.IsCompressed {
+		return int(t.Width * t.Height * 4)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(t.Data))
+	if err != nil {
+		return len(t.Data)
+	}
+
+	return cfg.Width * cfg.Height * 4
+}
+
+// String renders the stats as a human readable table.
+func (st *SceneStats) String() string {
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Nodes\t%d\t(depth %d)\n", st.Nodes, st.Depth)
+	fmt.Fprintf(w, "Meshes\t%d\t(%d instances)\n", st.Meshes, st.MeshInstances)
+	fmt.Fprintf(w, "Vertices\t%d\t\n", st.Vertices)
+	fmt.Fprintf(w, "Faces\t%d\t(%d points, %d lines, %d triangles, %d polygons)\n",
+		st.Faces, st.FacesByType.Points, st.FacesByType.Lines, st.FacesByType.Triangles, st.FacesByType.Polygons)
+	fmt.Fprintf(w, "Triangles\t%d\t\n", st.Triangles)
+	fmt.Fprintf(w, "Textures\t%d\t(%d texel bytes)\n", st.Textures, st.TexelBytes)
+	fmt.Fprintf(w, "Bones\t%d\t(max %d influences per vertex)\n", st.Bones, st.MaxInfluences)
+
+	if st.Bounds != nil {
+		fmt.Fprintf(w, "Bounds\t%v - %v\t\n", st.Bounds.Min, st.Bounds.Max)
+	} else {
+		fmt.Fprintf(w, "Bounds\tempty\t\n")
+	}
+
+	if len(st.Materials) > 0 {
+		fmt.Fprintf(w, "\nMaterial\tName\tMeshes\tInstances\tTriangles\n")
+		for _, m := range st.Materials {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\n", m.Index, m.Name, m.Meshes, m.Instances, m.Triangles)
+		}
+	}
+
+	if len(st.Animations) > 0 {
+		fmt.Fprintf(w, "\nAnimation\tSeconds\tTicks\tChannels\tMesh channels\n")
+		for _, a := range st.Animations {
+			fmt.Fprintf(w, "%s\t%g\t%g\t%d\t%d\n", a.Name, a.Seconds, a.Duration, a.Channels, a.MeshChannels)
+		}
+	}
+
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package assimp
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildStatsScene 构建用于统计的测试场景: 两个三角形实例, 一个四边形和线段网格, 一套骨骼和动画
+func buildStatsScene(t *testing.T) *Scene {
+	scene := buildFlattenScene()
+
+	scene.Meshes = append(scene.Meshes, &Mesh{
+		Name:          "mixed",
+		Vertices:      []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		Faces:         []Face{{Indices: []uint{0, 1, 2, 3}}, {Indices: []uint{0, 2}}},
+		MaterialIndex: 1,
+		Bones: []*Bone{
+			{Name: "a", Weights: []VertexWeight{{VertIndex: 0, Weight: 0.5}, {VertIndex: 1, Weight: 1}}},
+			{Name: "b", Weights: []VertexWeight{{VertIndex: 0, Weight: 0.5}, {VertIndex: 2, Weight: 0}}},
+		},
+	})
+	scene.Materials = append(scene.Materials, &Material{})
+	scene.FindNode("scaled").MeshIndicies = []uint{0, 2}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatal(err)
+	}
+	scene.Textures = []*EmbeddedTexture{
+		{Width: 2, Height: 2, Data: make([]byte, 16)},
+		{Width: uint(buf.Len()), FormatHint: "png", Data: buf.Bytes(), IsCompressed: true},
+	}
+
+	scene.Animations = []*Animation{{name: "walk", duration: 50, ticksPerSecond: 25, channels: []*NodeAnim{{name: "scaled"}}}}
+
+	return scene
+}
+
+// TestSceneStats 测试场景统计的各项计数
+func TestSceneStats(t *testing.T) {
+	st := buildStatsScene(t).Stats()
+
+	if st.Nodes != 3 || st.Depth != 2 {
+		t.Errorf("Expected 3 nodes with depth 2, got %d and %d", st.Nodes, st.Depth)
+	}
+	if st.Meshes != 3 || st.MeshInstances != 4 || st.Vertices != 10 {
+		t.Errorf("Expected 3 meshes, 4 instances and 10 vertices, got %d, %d, %d", st.Meshes, st.MeshInstances, st.Vertices)
+	}
+	if st.Faces != 4 || st.Triangles != 4 {
+		t.Errorf("Expected 4 faces and 4 triangles, got %d and %d", st.Faces, st.Triangles)
+	}
+	if st.FacesByType != (PrimitiveCounts{Lines: 1, Triangles: 2, Polygons: 1}) {
+		t.Errorf("Unexpected faces by type %+v", st.FacesByType)
+	}
+	if st.MeshesWithType != (PrimitiveCounts{Lines: 1, Triangles: 2, Polygons: 1}) {
+		t.Errorf("Unexpected meshes by type %+v", st.MeshesWithType)
+	}
+
+	if len(st.Materials) != 2 {
+		t.Fatalf("Expected 2 materials, got %d", len(st.Materials))
+	}
+	if m := st.Materials[0]; m.Name != "steel" || m.Meshes != 2 || m.Instances != 3 || m.Triangles != 2 {
+		t.Errorf("Unexpected usage of material 0: %+v", m)
+	}
+	if m := st.Materials[1]; m.Meshes != 1 || m.Instances != 1 || m.Triangles != 2 {
+		t.Errorf("Unexpected usage of material 1: %+v", m)
+	}
+
+	// 2x2未压缩纹理 + 4x2的PNG, 每个纹素4字节
+	if st.Textures != 2 || st.TexelBytes != 16+32 {
+		t.Errorf("Expected 2 textures with 48 texel bytes, got %d and %d", st.Textures, st.TexelBytes)
+	}
+
+	// 权重为0的骨骼不计为影响
+	if st.Bones != 2 || st.MaxInfluences != 2 {
+		t.Errorf("Expected 2 bones with at most 2 influences, got %d and %d", st.Bones, st.MaxInfluences)
+	}
+
+	if len(st.Animations) != 1 || st.Animations[0].Seconds != 2 || st.Animations[0].Channels != 1 {
+		t.Errorf("Unexpected animation stats %+v", st.Animations)
+	}
+
+	// scaled: x缩放2再平移1 -> [1,3], mirrored: x镜像再平移1 -> [0,1]
+	if st.Bounds == nil || st.Bounds.Min != (vec3.T{0, 0, 0}) || st.Bounds.Max != (vec3.T{3, 1, 0}) {
+		t.Errorf("Unexpected world bounds %v", st.Bounds)
+	}
+}
+
+// TestSceneStatsEmpty 测试空场景的统计
+func TestSceneStatsEmpty(t *testing.T) {
+	st := (&Scene{}).Stats()
+
+	if st.Nodes != 0 || st.Meshes != 0 || st.Bounds != nil {
+		t.Errorf("Expected empty stats, got %+v", st)
+	}
+	if !strings.Contains(st.String(), "empty") {
+		t.Error("Expected empty bounds in the table")
+	}
+}
+
+// TestSceneStatsRender 测试统计的表格和JSON输出
+func TestSceneStatsRender(t *testing.T) {
+	st := buildStatsScene(t).Stats()
+
+	table := st.String()
+	for _, want := range []string{"Nodes", "Triangles", "steel", "walk"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected table to contain %q:\n%s", want, table)
+		}
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+
+	var got SceneStats
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	if got.Triangles != st.Triangles || got.Materials[0].Name != "steel" || *got.Bounds != *st.Bounds {
+		t.Errorf("Expected stats to survive a JSON round trip, got %s", data)
+	}
+	if !bytes.Contains(data, []byte(`"facesByType":{"points":0,"lines":1`)) {
+		t.Errorf("Expected camel case JSON keys, got %s", data)
+	}
+}
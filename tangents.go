@@ -0,0 +1,817 @@
+package assimp
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/flywave/go3d/vec3"
+	"github.com/flywave/go3d/vec4"
+)
+
+// GenerateTangents computes Tangents and BitTangents from the normals and
+// the texture coordinates of channel uvChannel with the MikkTSpace
+// algorithm, so they match the tangent space renderers and bakers such as
+// Blender, Substance and xNormal use for normal maps.
+//
+// MikkTSpace computes a tangent per face corner. Where the corners sharing
+// a vertex get different tangents, e.g. on a mirrored UV seam, the vertex
+// is split and the faces, bones and anim meshes are updated. Bitangents
+// are the cross product of normal and tangent times the handedness sign,
+// see TangentsWithSign. Vertices only used by lines and points get a zero
+// tangent.
+func (m *Mesh) GenerateTangents(uvChannel int) error {
+
+	if uvChannel < 0 || uvChannel >= MaxTexCoords {
+		return fmt.Errorf("assimp: invalid texture coordinate channel %d", uvChannel)
+	}
+
+	n := len(m.Vertices)
+	if len(m.TexCoords[uvChannel]) < n {
+		return fmt.Errorf("assimp: mesh %q has no texture coordinates in channel %d", m.Name, uvChannel)
+	}
+	if len(m.Normals) < n {
+		return fmt.Errorf("assimp: mesh %q has no normals", m.Name)
+	}
+
+	faces := m.polygonFaces()
+	if len(faces) == 0 {
+		return fmt.Errorf("assimp: mesh %q has no triangles", m.Name)
+	}
+
+	m.Detach()
+
+	mk := newMikkContext(m, m.TexCoords[uvChannel], faces)
+	spaces := mk.generate()
+
+	type cornerTangent struct {
+		t    vec3.T
+		sign float32
+	}
+
+	values, _ := splitCorners(m, faces, func(f, i int) cornerTangent {
+		ts := spaces[mk.offsets[f]+i]
+		if ts.orient {
+			return cornerTangent{ts.os, 1}
+		}
+		return cornerTangent{ts.os, -1}
+	})
+
+	m.Tangents = make([]vec3.T, len(values))
+	m.BitTangents = make([]vec3.T, len(values))
+	for v, ct := range values {
+		nrm := m.Normals[v].Normalized()
+		m.Tangents[v] = ct.t
+		m.BitTangents[v] = vec3.Cross(&nrm, &ct.t)
+		m.BitTangents[v].Scale(ct.sign)
+	}
+
+	return nil
+}
+
+// TangentsWithSign returns the tangents as 4 component vectors whose w is
+// the handedness of the tangent frame: 1 if the bitangent equals the cross
+// product of normal and tangent, -1 if it is mirrored. This is the form
+// glTF and most shaders expect. It returns nil if the mesh has no tangents.
+func (m *Mesh) TangentsWithSign() []vec4.T {
+
+	if len(m.Tangents) == 0 {
+		return nil
+	}
+
+	frame := len(m.Normals) >= len(m.Tangents) && len(m.BitTangents) >= len(m.Tangents)
+
+	out := make([]vec4.T, len(m.Tangents))
+	for i, t := range m.Tangents {
+		sign := float32(1)
+		if frame {
+			sign = tangentSign(m.Normals[i], t, m.BitTangents[i])
+		}
+		out[i] = vec4.T{t[0], t[1], t[2], sign}
+	}
+
+	return out
+}
+
+// tangentSign returns -1 if the bitangent b points away from the cross
+// product of normal n and tangent t, and 1 otherwise.
+func tangentSign(n, t, b vec3.T) float32 {
+	c := vec3.Cross(&n, &t)
+	if vec3.Dot(&c, &b) < 0 {
+		return -1
+	}
+	return 1
+}
+
+// polygonFace is a face with at least three corners, see Mesh.polygonFaces.
+type polygonFace struct {
+	// face is the index of the face in the mesh.
+	face    int
+	indices []int
+	set     func(corner, v int)
+}
+
+// polygonFaces returns the faces with three or more corners, in either face
+// layout. Setting a corner writes through to the mesh.
+func (m *Mesh) polygonFaces() []polygonFace {
+
+	var faces []polygonFace
+
+	for fi, f := range m.Faces {
+		if len(f.Indices) < 3 {
+			continue
+		}
+		idx := make([]int, len(f.Indices))
+		for i, v := range f.Indices {
+			idx[i] = int(v)
+		}
+		faces = append(faces, polygonFace{fi, idx, func(corner, v int) { f.Indices[corner] = uint(v) }})
+	}
+
+	for i := 0; i+1 < len(m.FaceOffsets); i++ {
+		flat := m.Indices[m.FaceOffsets[i]:m.FaceOffsets[i+1]]
+		if len(flat) < 3 {
+			continue
+		}
+		idx := make([]int, len(flat))
+		for j, v := range flat {
+			idx[j] = int(v)
+		}
+		faces = append(faces, polygonFace{i, idx, func(corner, v int) { flat[corner] = uint32(v) }})
+	}
+
+	return faces
+}
+
+// splitCorners gives each face corner the value value(f, i) returns and
+// returns the resulting value of every vertex. A vertex whose corners need
+// different values is split, and the faces are updated to use the copies.
+// assigned reports which vertices are used by any of the faces.
+func splitCorners[T comparable](m *Mesh, faces []polygonFace, value func(f, i int) T) (values []T, assigned []bool) {
+
+	n := len(m.Vertices)
+	values = make([]T, n)
+	assigned = make([]bool, n)
+
+	split := newVertexSplitter(m)
+	// copies lists the vertices split off each vertex.
+	copies := make(map[int][]int)
+
+	for f, face := range faces {
+		for i, v := range face.indices {
+			val := value(f, i)
+
+			switch {
+			case !assigned[v]:
+				assigned[v] = true
+				values[v] = val
+			case values[v] == val:
+			default:
+				same := func(c int) bool { return values[c] == val }
+				if j := slices.IndexFunc(copies[v], same); j >= 0 {
+					face.set(i, copies[v][j])
+					continue
+				}
+
+				c := split.duplicate(v)
+				copies[v] = append(copies[v], c)
+				values = append(values, val)
+				assigned = append(assigned, true)
+				face.set(i, c)
+			}
+		}
+	}
+
+	return values, assigned
+}
+
+// vertexSplitter duplicates vertices of a mesh together with their bone
+// weights and anim mesh data.
+type vertexSplitter struct {
+	m *Mesh
+	// weights lists the bone weights of each vertex, built on first use.
+	weights map[int][]VertexWeight
+	bones   map[int][]int
+}
+
+func newVertexSplitter(m *Mesh) *vertexSplitter {
+	return &vertexSplitter{m: m}
+}
+
+// duplicate appends a copy of vertex v and returns its index.
+func (s *vertexSplitter) duplicate(v int) int {
+
+	m := s.m
+	n := len(m.Vertices)
+
+	m.Vertices = append(m.Vertices, m.Vertices[v])
+	m.Normals = appendVertex(m.Normals, v, n)
+	m.Tangents = appendVertex(m.Tangents, v, n)
+	m.BitTangents = appendVertex(m.BitTangents, v, n)
+	for i := range m.ColorSets {
+		m.ColorSets[i] = appendVertex(m.ColorSets[i], v, n)
+	}
+	for i := range m.TexCoords {
+		m.TexCoords[i] = appendVertex(m.TexCoords[i], v, n)
+	}
+
+	for _, am := range m.AnimMeshes {
+		am.Vertices = appendVertex(am.Vertices, v, n)
+		am.Normals = appendVertex(am.Normals, v, n)
+		am.Tangents = appendVertex(am.Tangents, v, n)
+		am.BitTangents = appendVertex(am.BitTangents, v, n)
+		for i := range am.Colors {
+			am.Colors[i] = appendVertex(am.Colors[i], v, n)
+		}
+		for i := range am.TexCoords {
+			am.TexCoords[i] = appendVertex(am.TexCoords[i], v, n)
+		}
+	}
+
+	if len(m.Bones) > 0 {
+		if s.weights == nil {
+			s.weights = make(map[int][]VertexWeight)
+			s.bones = make(map[int][]int)
+			for b, bone := range m.Bones {
+				for _, w := range bone.Weights {
+					s.weights[int(w.VertIndex)] = append(s.weights[int(w.VertIndex)], w)
+					s.bones[int(w.VertIndex)] = append(s.bones[int(w.VertIndex)], b)
+				}
+			}
+		}
+		for i, b := range s.bones[v] {
+			w := s.weights[v][i]
+			w.VertIndex = uint(n)
+			m.Bones[b].Weights = append(m.Bones[b].Weights, w)
+		}
+	}
+
+	return n
+}
+
+// appendVertex appends a copy of s[v] if s holds a value for each of the n
+// vertices, and returns s unchanged otherwise.
+func appendVertex[T any](s []T, v, n int) []T {
+	if len(s) != n {
+		return s
+	}
+	return append(s, s[v])
+}
+
+// MikkTSpace follows the reference implementation by Morten S. Mikkelsen,
+// mikktspace.c, with the default angular threshold of 180 degrees.
+
+type mikkFlag uint8
+
+const (
+	mikkDegenerate mikkFlag = 1 << iota
+	mikkQuadOneDegenerate
+	mikkGroupWithAny
+	mikkOrientPreserving
+)
+
+type mikkTri struct {
+	// corners holds the shared vertex of each corner, vert the number of
+	// the corner within the original face.
+	corners [3]int
+	vert    [3]int
+	face    int
+
+	flags     mikkFlag
+	neighbors [3]int
+	groups    [3]*mikkGroup
+
+	os, ot     vec3.T
+	magS, magT float32
+}
+
+type mikkGroup struct {
+	rep    int
+	orient bool
+	tris   []int
+}
+
+type mikkTSpace struct {
+	os, ot     vec3.T
+	magS, magT float32
+	counter    int
+	orient     bool
+}
+
+type mikkContext struct {
+	pos     []vec3.T
+	normals []vec3.T
+	uvs     []vec3.T
+
+	faces   []polygonFace
+	offsets []int
+	// shared maps each vertex to the first vertex with the same position,
+	// normal and texture coordinate.
+	shared []int
+
+	tris []mikkTri
+	good int
+}
+
+func newMikkContext(m *Mesh, uvs []vec3.T, faces []polygonFace) *mikkContext {
+
+	mk := &mikkContext{
+		pos:     m.Vertices,
+		normals: make([]vec3.T, len(m.Vertices)),
+		uvs:     uvs,
+		faces:   faces,
+		offsets: make([]int, len(faces)+1),
+		shared:  make([]int, len(m.Vertices)),
+	}
+
+	for i, nrm := range m.Normals[:len(m.Vertices)] {
+		mk.normals[i] = nrm.Normalized()
+	}
+
+	type vertexKey struct {
+		p, n vec3.T
+		u, v float32
+	}
+	first := make(map[vertexKey]int, len(m.Vertices))
+	for i := range m.Vertices {
+		k := vertexKey{mk.pos[i], mk.normals[i], uvs[i][0], uvs[i][1]}
+		if j, ok := first[k]; ok {
+			mk.shared[i] = j
+		} else {
+			first[k] = i
+			mk.shared[i] = i
+		}
+	}
+
+	for f, face := range faces {
+		mk.offsets[f+1] = mk.offsets[f] + len(face.indices)
+	}
+
+	return mk
+}
+
+func (mk *mikkContext) texCoord(v int) vec3.T {
+	uv := mk.uvs[v]
+	return vec3.T{uv[0], uv[1], 1}
+}
+
+// generate returns the tangent space of every face corner, indexed by
+// offsets[face] + corner.
+func (mk *mikkContext) generate() []mikkTSpace {
+
+	mk.triangulate()
+	mk.degenPrologue()
+	mk.initTriInfo()
+	mk.buildNeighbors()
+	groups := mk.buildGroups()
+
+	spaces := make([]mikkTSpace, mk.offsets[len(mk.faces)])
+	for i := range spaces {
+		spaces[i] = mikkTSpace{os: vec3.UnitX, ot: vec3.UnitY, magS: 1, magT: 1}
+	}
+
+	mk.generateTSpaces(spaces, groups)
+	mk.degenEpilogue(spaces)
+
+	return spaces
+}
+
+// triangulate splits quads along the diagonal that is shorter in texture
+// space, and other polygons as fans.
+func (mk *mikkContext) triangulate() {
+
+	for f, face := range mk.faces {
+		idx := face.indices
+
+		add := func(a, b, c int) {
+			mk.tris = append(mk.tris, mikkTri{
+				corners: [3]int{mk.shared[idx[a]], mk.shared[idx[b]], mk.shared[idx[c]]},
+				vert:    [3]int{a, b, c},
+				face:    f,
+			})
+		}
+
+		switch len(idx) {
+		case 3:
+			add(0, 1, 2)
+		case 4:
+			t0, t1, t2, t3 := mk.texCoord(idx[0]), mk.texCoord(idx[1]), mk.texCoord(idx[2]), mk.texCoord(idx[3])
+			d02 := vec3.SquareDistance(&t2, &t0)
+			d13 := vec3.SquareDistance(&t3, &t1)
+
+			diag02 := d02 < d13
+			if d02 == d13 {
+				p0, p1, p2, p3 := mk.pos[idx[0]], mk.pos[idx[1]], mk.pos[idx[2]], mk.pos[idx[3]]
+				diag02 = vec3.SquareDistance(&p3, &p1) >= vec3.SquareDistance(&p2, &p0)
+			}
+
+			if diag02 {
+				add(0, 1, 2)
+				add(0, 2, 3)
+			} else {
+				add(0, 1, 3)
+				add(1, 2, 3)
+			}
+		default:
+			for i := 1; i+1 < len(idx); i++ {
+				add(0, i, i+1)
+			}
+		}
+	}
+}
+
+// isQuadPair reports whether triangles t and t+1 are the two halves of a
+// quad.
+func (mk *mikkContext) isQuadPair(t int) bool {
+	return t+1 < len(mk.tris) && mk.tris[t].face == mk.tris[t+1].face && len(mk.faces[mk.tris[t].face].indices) == 4
+}
+
+// degenPrologue marks triangles with coincident corners and moves them
+// behind the good triangles.
+func (mk *mikkContext) degenPrologue() {
+
+	for t := range mk.tris {
+		tri := &mk.tris[t]
+		p0, p1, p2 := mk.pos[tri.corners[0]], mk.pos[tri.corners[1]], mk.pos[tri.corners[2]]
+		if p0 == p1 || p0 == p2 || p1 == p2 {
+			tri.flags |= mikkDegenerate
+		}
+	}
+
+	for t := 0; t+1 < len(mk.tris); t++ {
+		if !mk.isQuadPair(t) {
+			continue
+		}
+		if (mk.tris[t].flags^mk.tris[t+1].flags)&mikkDegenerate != 0 {
+			mk.tris[t].flags |= mikkQuadOneDegenerate
+			mk.tris[t+1].flags |= mikkQuadOneDegenerate
+		}
+		t++
+	}
+
+	good := make([]mikkTri, 0, len(mk.tris))
+	var degenerate []mikkTri
+	for _, tri := range mk.tris {
+		if tri.flags&mikkDegenerate != 0 {
+			degenerate = append(degenerate, tri)
+		} else {
+			good = append(good, tri)
+		}
+	}
+
+	mk.good = len(good)
+	mk.tris = append(good, degenerate...)
+}
+
+// initTriInfo computes the first order derivatives of each good triangle.
+func (mk *mikkContext) initTriInfo() {
+
+	for t := range mk.tris {
+		tri := &mk.tris[t]
+		tri.neighbors = [3]int{-1, -1, -1}
+		tri.flags |= mikkGroupWithAny
+	}
+
+	for t := range mk.tris[:mk.good] {
+		tri := &mk.tris[t]
+
+		v1, v2, v3 := mk.pos[tri.corners[0]], mk.pos[tri.corners[1]], mk.pos[tri.corners[2]]
+		t1, t2, t3 := mk.texCoord(tri.corners[0]), mk.texCoord(tri.corners[1]), mk.texCoord(tri.corners[2])
+
+		t21x, t21y := t2[0]-t1[0], t2[1]-t1[1]
+		t31x, t31y := t3[0]-t1[0], t3[1]-t1[1]
+		d1 := vec3.Sub(&v2, &v1)
+		d2 := vec3.Sub(&v3, &v1)
+
+		area := t21x*t31y - t21y*t31x
+		if area > 0 {
+			tri.flags |= mikkOrientPreserving
+		}
+
+		os, ds := d1.Scaled(t31y), d2.Scaled(t21y)
+		os.Sub(&ds)
+		ot, dt := d1.Scaled(-t31x), d2.Scaled(t21x)
+		ot.Add(&dt)
+
+		if !mikkNotZero(area) {
+			continue
+		}
+
+		absArea := absf(area)
+		lenOs, lenOt := os.Length(), ot.Length()
+		sign := float32(1)
+		if tri.flags&mikkOrientPreserving == 0 {
+			sign = -1
+		}
+		if mikkNotZero(lenOs) {
+			tri.os = os.Scaled(sign / lenOs)
+		}
+		if mikkNotZero(lenOt) {
+			tri.ot = ot.Scaled(sign / lenOt)
+		}
+		tri.magS = lenOs / absArea
+		tri.magT = lenOt / absArea
+
+		if mikkNotZero(tri.magS) && mikkNotZero(tri.magT) {
+			tri.flags &^= mikkGroupWithAny
+		}
+	}
+
+	// Force the two halves of a healthy quad to the same orientation.
+	for t := 0; t+1 < mk.good; t++ {
+		if !mk.isQuadPair(t) {
+			continue
+		}
+
+		a, b := &mk.tris[t], &mk.tris[t+1]
+		if (a.flags^b.flags)&mikkOrientPreserving != 0 {
+			src, dst := a, b
+			if b.flags&mikkGroupWithAny == 0 && mk.texArea(a) < mk.texArea(b) {
+				src, dst = b, a
+			}
+			dst.flags = dst.flags&^mikkOrientPreserving | src.flags&mikkOrientPreserving
+		}
+		t++
+	}
+}
+
+func (mk *mikkContext) texArea(tri *mikkTri) float32 {
+	t1, t2, t3 := mk.texCoord(tri.corners[0]), mk.texCoord(tri.corners[1]), mk.texCoord(tri.corners[2])
+	area := (t2[0]-t1[0])*(t3[1]-t1[1]) - (t2[1]-t1[1])*(t3[0]-t1[0])
+	return absf(area) * 0.5
+}
+
+// buildNeighbors links each edge of a good triangle to the good triangle
+// sharing it in the opposite direction.
+func (mk *mikkContext) buildNeighbors() {
+
+	type edgeRef struct{ tri, edge int }
+	edges := make(map[[2]int][]edgeRef)
+
+	for t := range mk.tris[:mk.good] {
+		c := mk.tris[t].corners
+		for e := range 3 {
+			k := [2]int{c[e], c[(e+1)%3]}
+			edges[k] = append(edges[k], edgeRef{t, e})
+		}
+	}
+
+	for t := range mk.tris[:mk.good] {
+		tri := &mk.tris[t]
+		for e := range 3 {
+			if tri.neighbors[e] >= 0 {
+				continue
+			}
+			k := [2]int{tri.corners[(e+1)%3], tri.corners[e]}
+			for _, r := range edges[k] {
+				other := &mk.tris[r.tri]
+				if r.tri != t && other.neighbors[r.edge] < 0 {
+					tri.neighbors[e] = r.tri
+					other.neighbors[r.edge] = t
+					break
+				}
+			}
+		}
+	}
+}
+
+// buildGroups groups the triangles around each vertex that are connected
+// and have the same orientation.
+func (mk *mikkContext) buildGroups() []*mikkGroup {
+
+	var groups []*mikkGroup
+
+	for t := range mk.tris[:mk.good] {
+		tri := &mk.tris[t]
+		if tri.flags&mikkGroupWithAny != 0 {
+			continue
+		}
+
+		for i := range 3 {
+			if tri.groups[i] != nil {
+				continue
+			}
+
+			g := &mikkGroup{rep: tri.corners[i], orient: tri.flags&mikkOrientPreserving != 0}
+			groups = append(groups, g)
+			tri.groups[i] = g
+			g.tris = append(g.tris, t)
+
+			if l := tri.neighbors[i]; l >= 0 {
+				mk.assignRecur(l, g)
+			}
+			if r := tri.neighbors[(i+2)%3]; r >= 0 {
+				mk.assignRecur(r, g)
+			}
+		}
+	}
+
+	return groups
+}
+
+func (mk *mikkContext) assignRecur(t int, g *mikkGroup) bool {
+
+	tri := &mk.tris[t]
+
+	i := slices.Index(tri.corners[:], g.rep)
+	if i < 0 {
+		return false
+	}
+	if tri.groups[i] == g {
+		return true
+	}
+	if tri.groups[i] != nil {
+		return false
+	}
+
+	// The first group a triangle of unknown orientation joins decides it.
+	if tri.flags&mikkGroupWithAny != 0 && tri.groups == [3]*mikkGroup{} {
+		tri.flags &^= mikkOrientPreserving
+		if g.orient {
+			tri.flags |= mikkOrientPreserving
+		}
+	}
+
+	if (tri.flags&mikkOrientPreserving != 0) != g.orient {
+		return false
+	}
+
+	g.tris = append(g.tris, t)
+	tri.groups[i] = g
+
+	if l := tri.neighbors[i]; l >= 0 {
+		mk.assignRecur(l, g)
+	}
+	if r := tri.neighbors[(i+2)%3]; r >= 0 {
+		mk.assignRecur(r, g)
+	}
+
+	return true
+}
+
+// project returns v projected onto the plane with normal n, normalized.
+func mikkProject(v, n vec3.T) vec3.T {
+	p := n.Scaled(vec3.Dot(&n, &v))
+	v.Sub(&p)
+	return v.Normalized()
+}
+
+func (mk *mikkContext) generateTSpaces(spaces []mikkTSpace, groups []*mikkGroup) {
+
+	// The default angular threshold of 180 degrees.
+	const thresCos = -1
+
+	for _, g := range groups {
+		for _, f := range g.tris {
+			tri := &mk.tris[f]
+			index := slices.Index(tri.groups[:], g)
+
+			n := mk.normals[tri.corners[index]]
+			os := mikkProject(tri.os, n)
+			ot := mikkProject(tri.ot, n)
+
+			var members []int
+			for _, t := range g.tris {
+				other := &mk.tris[t]
+				os2 := mikkProject(other.os, n)
+				ot2 := mikkProject(other.ot, n)
+
+				withAny := (tri.flags|other.flags)&mikkGroupWithAny != 0
+				sameFace := tri.face == other.face
+				if withAny || sameFace || vec3.Dot(&os, &os2) > thresCos && vec3.Dot(&ot, &ot2) > thresCos {
+					members = append(members, t)
+				}
+			}
+
+			slices.Sort(members)
+			ts := mk.evalTSpace(members, g.rep)
+
+			out := &spaces[mk.offsets[tri.face]+tri.vert[index]]
+			if out.counter == 1 {
+				*out = mikkAvgTSpace(*out, ts)
+				out.counter = 2
+			} else {
+				*out = ts
+				out.counter = 1
+			}
+			out.orient = g.orient
+		}
+	}
+}
+
+// evalTSpace averages the derivatives of the triangles at vertex rep,
+// weighted by their angle at the vertex.
+func (mk *mikkContext) evalTSpace(tris []int, rep int) mikkTSpace {
+
+	var res mikkTSpace
+	var angleSum float32
+
+	for _, t := range tris {
+		tri := &mk.tris[t]
+		if tri.flags&mikkGroupWithAny != 0 {
+			continue
+		}
+
+		i := slices.Index(tri.corners[:], rep)
+		p0 := mk.pos[tri.corners[(i+1)%3]]
+		p1 := mk.pos[tri.corners[i]]
+		p2 := mk.pos[tri.corners[(i+2)%3]]
+
+		n := mk.normals[rep]
+		os := mikkProject(tri.os, n)
+		ot := mikkProject(tri.ot, n)
+
+		v1 := mikkProject(vec3.Sub(&p0, &p1), n)
+		v2 := mikkProject(vec3.Sub(&p2, &p1), n)
+
+		cos := min(max(vec3.Dot(&v1, &v2), -1), 1)
+		angle := float32(math.Acos(float64(cos)))
+
+		os.Scale(angle)
+		ot.Scale(angle)
+		res.os.Add(&os)
+		res.ot.Add(&ot)
+		res.magS += angle * tri.magS
+		res.magT += angle * tri.magT
+		angleSum += angle
+	}
+
+	res.os.Normalize()
+	res.ot.Normalize()
+	if angleSum > 0 {
+		res.magS /= angleSum
+		res.magT /= angleSum
+	}
+
+	return res
+}
+
+func mikkAvgTSpace(a, b mikkTSpace) mikkTSpace {
+
+	if a.magS == b.magS && a.magT == b.magT && a.os == b.os && a.ot == b.ot {
+		return a
+	}
+
+	res := mikkTSpace{magS: 0.5 * (a.magS + b.magS), magT: 0.5 * (a.magT + b.magT)}
+	res.os = vec3.Add(&a.os, &b.os)
+	res.ot = vec3.Add(&a.ot, &b.ot)
+	res.os.Normalize()
+	res.ot.Normalize()
+
+	return res
+}
+
+// degenEpilogue gives the corners of degenerate triangles the tangent space
+// of a good triangle at the same vertex.
+func (mk *mikkContext) degenEpilogue(spaces []mikkTSpace) {
+
+	firstGood := make(map[int]int)
+	for t := mk.good - 1; t >= 0; t-- {
+		for i := 2; i >= 0; i-- {
+			firstGood[mk.tris[t].corners[i]] = t*3 + i
+		}
+	}
+
+	for _, tri := range mk.tris[mk.good:] {
+		if tri.flags&mikkQuadOneDegenerate != 0 {
+			continue
+		}
+		for i := range 3 {
+			j, ok := firstGood[tri.corners[i]]
+			if !ok {
+				continue
+			}
+			src := &mk.tris[j/3]
+			spaces[mk.offsets[tri.face]+tri.vert[i]] = spaces[mk.offsets[src.face]+src.vert[j%3]]
+		}
+	}
+
+	// The corner of a quad missing from its one good triangle copies the
+	// corner at the same position.
+	for _, tri := range mk.tris[:mk.good] {
+		if tri.flags&mikkQuadOneDegenerate == 0 {
+			continue
+		}
+
+		missing := 0
+		for missing < 3 && slices.Contains(tri.vert[:], missing) {
+			missing++
+		}
+
+		idx := mk.faces[tri.face].indices
+		dst := mk.pos[idx[missing]]
+		for _, v := range tri.vert {
+			if mk.pos[idx[v]] == dst {
+				off := mk.offsets[tri.face]
+				spaces[off+missing] = spaces[off+v]
+				break
+			}
+		}
+	}
+}
+
+// mikkNotZero reports whether f is larger in magnitude than the smallest
+// normal float32, like NotZero in mikktspace.c.
+func mikkNotZero(f float32) bool {
+	return absf(f) > 0x1p-126
+}
@@ -0,0 +1,177 @@
+package assimp
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// approxTangent 比较切线和参考值
+func approxTangent(t *testing.T, m *Mesh, v int, want vec3.T, sign float32) {
+	t.Helper()
+
+	tangents := m.TangentsWithSign()
+	got := tangents[v]
+	if !approxVec3(vec3.T{got[0], got[1], got[2]}, want) || got[3] != sign {
+		t.Errorf("Expected tangent %v with sign %v for vertex %d, got %v", want, sign, v, got)
+	}
+
+	// 副切线 = 符号 * cross(法线, 切线)
+	n := m.Normals[v]
+	b := vec3.Cross(&n, &want)
+	b.Scale(sign)
+	if !approxVec3(m.BitTangents[v], b) {
+		t.Errorf("Expected bitangent %v for vertex %d, got %v", b, v, m.BitTangents[v])
+	}
+}
+
+// TestGenerateTangentsQuad 测试平面四边形和退化三角形的切线
+func TestGenerateTangentsQuad(t *testing.T) {
+	for _, flat := range []bool{false, true} {
+		mesh := &Mesh{
+			// 顶点4与顶点1完全相同
+			Vertices: []vec3.T{{0, 0, 0}, {2, 0, 0}, {2, 1, 0}, {0, 1, 0}, {2, 0, 0}},
+			Normals:  []vec3.T{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+			Faces:    []Face{{Indices: []uint{0, 1, 2, 3}}, {Indices: []uint{0, 1, 4}}},
+		}
+		mesh.TexCoords[1] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}, {1, 0, 0}}
+		if flat {
+			mesh.Faces = nil
+			mesh.Indices = []uint32{0, 1, 2, 3, 0, 1, 4}
+			mesh.FaceOffsets = []uint32{0, 4, 7}
+		}
+
+		if err := mesh.GenerateTangents(1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(mesh.Vertices) != 5 {
+			t.Errorf("Expected no vertices to be split, got %d", len(mesh.Vertices))
+		}
+
+		// 退化三角形的角从相同顶点的正常三角形获得切线
+		for v := range mesh.Vertices {
+			approxTangent(t, mesh, v, vec3.T{1, 0, 0}, 1)
+		}
+	}
+}
+
+// TestGenerateTangentsAngleWeighted 测试共享顶点按夹角加权平均
+func TestGenerateTangentsAngleWeighted(t *testing.T) {
+	mesh := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {-1, 1, 0}},
+		Normals:  []vec3.T{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}, {Indices: []uint{0, 2, 3}}},
+	}
+	// 第一个三角形的切线为(1,0,0), 第二个为(1,1,0)/√2
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {-1, 2, 0}}
+
+	if err := mesh.GenerateTangents(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 顶点0处两个三角形的夹角分别为90度和45度, 顶点2处为45度和90度
+	approxTangent(t, mesh, 0, vec3.T{0.96753822, 0.25272473, 0}, 1)
+	approxTangent(t, mesh, 1, vec3.T{1, 0, 0}, 1)
+	approxTangent(t, mesh, 2, vec3.T{0.86285621, 0.50544947, 0}, 1)
+	approxTangent(t, mesh, 3, vec3.T{math.Sqrt2 / 2, math.Sqrt2 / 2, 0}, 1)
+}
+
+// TestGenerateTangentsMirroredSeam 测试镜像UV接缝处的顶点拆分
+func TestGenerateTangentsMirroredSeam(t *testing.T) {
+	mesh := &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {2, 0, 0}},
+		Normals:  []vec3.T{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}, {Indices: []uint{1, 3, 2}}},
+		Bones:    []*Bone{{Name: "bone", Weights: []VertexWeight{{VertIndex: 2, Weight: 1}}}},
+	}
+	// 右侧三角形的U坐标沿接缝x=1镜像
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 0, 0}}
+
+	if err := mesh.GenerateTangents(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(mesh.Vertices) != 6 || len(mesh.Normals) != 6 || len(mesh.TexCoords[0]) != 6 {
+		t.Fatalf("Expected the 2 seam vertices to be split, got %d vertices", len(mesh.Vertices))
+	}
+	if f := mesh.Faces[1].Indices; f[0] != 4 || f[1] != 3 || f[2] != 5 {
+		t.Errorf("Expected the mirrored face to use the split vertices, got %v", f)
+	}
+
+	approxTangent(t, mesh, 0, vec3.T{1, 0, 0}, 1)
+	approxTangent(t, mesh, 1, vec3.T{1, 0, 0}, 1)
+	approxTangent(t, mesh, 3, vec3.T{-1, 0, 0}, -1)
+	approxTangent(t, mesh, 4, vec3.T{-1, 0, 0}, -1)
+	approxTangent(t, mesh, 5, vec3.T{-1, 0, 0}, -1)
+
+	if w := mesh.Bones[0].Weights; len(w) != 2 || w[1].VertIndex != 5 {
+		t.Errorf("Expected the bone weight to be copied to vertex 5, got %v", w)
+	}
+}
+
+// TestGenerateTangentsReferenceCube 测试与外部工具生成的立方体切线一致
+func TestGenerateTangentsReferenceCube(t *testing.T) {
+	// 切线和符号来自Khronos glTF示例模型Cube的TANGENT数据, 包含镜像UV的面
+	data, err := os.ReadFile(filepath.Join("testdata", "cube_tangents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ref struct {
+		Indices   []uint       `json:"indices"`
+		Positions []vec3.T     `json:"positions"`
+		Normals   []vec3.T     `json:"normals"`
+		TexCoords [][2]float32 `json:"texcoords"`
+		Tangents  [][4]float32 `json:"tangents"`
+	}
+	if err := json.Unmarshal(data, &ref); err != nil {
+		t.Fatal(err)
+	}
+
+	mesh := &Mesh{Vertices: ref.Positions, Normals: ref.Normals}
+	for i := 0; i < len(ref.Indices); i += 3 {
+		mesh.Faces = append(mesh.Faces, Face{Indices: ref.Indices[i : i+3]})
+	}
+	for _, uv := range ref.TexCoords {
+		mesh.TexCoords[0] = append(mesh.TexCoords[0], vec3.T{uv[0], uv[1], 0})
+	}
+
+	if err := mesh.GenerateTangents(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mesh.Vertices) != len(ref.Positions) {
+		t.Fatalf("Expected no vertices to be split, got %d", len(mesh.Vertices))
+	}
+
+	for v, want := range ref.Tangents {
+		approxTangent(t, mesh, v, vec3.T{want[0], want[1], want[2]}, want[3])
+	}
+}
+
+// TestGenerateTangentsErrors 测试缺少数据时的错误
+func TestGenerateTangentsErrors(t *testing.T) {
+	mesh := triangleMesh("tri", 0)
+	if err := mesh.GenerateTangents(0); err == nil {
+		t.Error("Expected an error for a mesh without texture coordinates")
+	}
+	if err := mesh.GenerateTangents(MaxTexCoords); err == nil {
+		t.Error("Expected an error for an invalid channel")
+	}
+
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	mesh.Normals = nil
+	if err := mesh.GenerateTangents(0); err == nil {
+		t.Error("Expected an error for a mesh without normals")
+	}
+
+	mesh = triangleMesh("line", 0)
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	mesh.Faces = []Face{{Indices: []uint{0, 1}}}
+	if err := mesh.GenerateTangents(0); err == nil {
+		t.Error("Expected an error for a mesh without triangles")
+	}
+}
@@ -0,0 +1,145 @@
+package assimp
+
+import "fmt"
+
+// ValidationIssueKind classifies a problem found by Scene.Validate.
+type ValidationIssueKind int
+
+const (
+	// IssueMeshIndexOutOfRange is a node mesh index beyond Scene.Meshes.
+	IssueMeshIndexOutOfRange ValidationIssueKind = iota
+	// IssueCycle is a node that is its own ancestor.
+	IssueCycle
+	// IssueDuplicateName is a node name used by more than one node.
+	// Animations and bones refer to nodes by name, so they become ambiguous.
+	IssueDuplicateName
+	// IssueParentMismatch is a node whose Parent isn't the node listing it
+	// as a child, or a node listed as a child more than once.
+	IssueParentMismatch
+	// IssueNilNode is a nil entry in Node.Children.
+	IssueNilNode
+)
+
+func (k ValidationIssueKind) String() string {
+	switch k {
+	case IssueMeshIndexOutOfRange:
+		return "mesh index out of range"
+	case IssueCycle:
+		return "cycle"
+	case IssueDuplicateName:
+		return "duplicate name"
+	case IssueParentMismatch:
+		return "parent mismatch"
+	case IssueNilNode:
+		return "nil node"
+	default:
+		return fmt.Sprintf("ValidationIssueKind(%d)", int(k))
+	}
+}
+
+// ValidationIssue is a problem in the node hierarchy of a Scene.
+type ValidationIssue struct {
+	Kind ValidationIssueKind
+	// Node is the node the issue was found at.
+	Node *Node
+	// Path is the slash separated list of node names from the root to Node.
+	Path    string
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	return i.Kind.String() + " at " + i.Path + ": " + i.Message
+}
+
+// Validate checks the node hierarchy of the Go scene for mesh indices out of
+// range, cycles, duplicate node names and Parent links that don't match the
+// Children lists. It returns nil if no issues were found.
+func (s *Scene) Validate() []ValidationIssue {
+
+	if s.RootNode == nil {
+		return nil
+	}
+
+	v := &validator{
+		scene:   s,
+		onStack: map[*Node]bool{},
+		visited: map[*Node]bool{},
+		names:   map[string]string{},
+	}
+
+	if s.RootNode.Parent != nil {
+		v.report(IssueParentMismatch, s.RootNode, s.RootNode.Name, "root node has a parent")
+	}
+
+	v.visit(s.RootNode, s.RootNode.Name)
+	return v.issues
+}
+
+type validator struct {
+	scene   *Scene
+	onStack map[*Node]bool
+	visited map[*Node]bool
+	// names maps node names to the path of their first use.
+	names  map[string]string
+	issues []ValidationIssue
+}
+
+func (v *validator) report(kind ValidationIssueKind, n *Node, path string, format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{
+		Kind:    kind,
+		Node:    n,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) visit(n *Node, path string) {
+
+	v.onStack[n] = true
+	v.visited[n] = true
+	defer delete(v.onStack, n)
+
+	if n.Name != "" {
+		if first, ok := v.names[n.Name]; ok {
+			v.report(IssueDuplicateName, n, path, "name %q is also used at %s", n.Name, first)
+		} else {
+			v.names[n.Name] = path
+		}
+	}
+
+	for _, mi := range n.MeshIndicies {
+		if mi >= uint(len(v.scene.Meshes)) {
+			v.report(IssueMeshIndexOutOfRange, n, path, "mesh index %d, scene has %d meshes", mi, len(v.scene.Meshes))
+		}
+	}
+
+	for i, c := range n.Children {
+
+		if c == nil {
+			v.report(IssueNilNode, n, path, "child %d is nil", i)
+			continue
+		}
+
+		childPath := path + "/" + c.Name
+
+		if v.onStack[c] {
+			v.report(IssueCycle, c, childPath, "node %q is its own ancestor", c.Name)
+			continue
+		}
+
+		if v.visited[c] {
+			v.report(IssueParentMismatch, c, childPath, "node %q is a child of more than one node", c.Name)
+			continue
+		}
+
+		if c.Parent != n {
+			parent := "nil"
+			if c.Parent != nil {
+				parent = fmt.Sprintf("%q", c.Parent.Name)
+			}
+			v.report(IssueParentMismatch, c, childPath, "parent is %s, listed as a child of %q", parent, n.Name)
+		}
+
+		v.visit(c, childPath)
+	}
+}
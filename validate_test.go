@@ -0,0 +1,155 @@
+package assimp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// buildTestHierarchy 构建一个三层的节点树: root -> (arm -> hand, legs)
+func buildTestHierarchy() *Scene {
+	root := &Node{Name: "root"}
+	arm := &Node{Name: "arm", Parent: root}
+	hand := &Node{Name: "hand", Parent: arm, MeshIndicies: []uint{0}}
+	legs := &Node{Name: "legs", Parent: root, MeshIndicies: []uint{1}}
+	arm.Children = []*Node{hand}
+	root.Children = []*Node{arm, legs}
+
+	return &Scene{RootNode: root, Meshes: []*Mesh{{}, {}}}
+}
+
+// issuesOfKind 返回指定类型的问题
+func issuesOfKind(issues []ValidationIssue, kind ValidationIssueKind) []ValidationIssue {
+	var out []ValidationIssue
+	for _, issue := range issues {
+		if issue.Kind == kind {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// TestValidateValidScene 测试合法场景没有问题
+func TestValidateValidScene(t *testing.T) {
+	if issues := buildTestHierarchy().Validate(); issues != nil {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+
+	if issues := (&Scene{}).Validate(); issues != nil {
+		t.Errorf("Expected no issues for an empty scene, got %v", issues)
+	}
+}
+
+// TestValidateMeshIndexOutOfRange 测试越界的网格索引
+func TestValidateMeshIndexOutOfRange(t *testing.T) {
+	scene := buildTestHierarchy()
+	hand := scene.RootNode.Children[0].Children[0]
+	hand.MeshIndicies = []uint{0, 5}
+
+	issues := issuesOfKind(scene.Validate(), IssueMeshIndexOutOfRange)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 mesh index issue, got %v", issues)
+	}
+
+	if issues[0].Node != hand || issues[0].Path != "root/arm/hand" {
+		t.Errorf("Expected issue at root/arm/hand, got %v", issues[0])
+	}
+}
+
+// TestValidateCycle 测试环路检测且不会无限递归
+func TestValidateCycle(t *testing.T) {
+	scene := buildTestHierarchy()
+	hand := scene.RootNode.Children[0].Children[0]
+	hand.Children = []*Node{scene.RootNode.Children[0]}
+
+	issues := issuesOfKind(scene.Validate(), IssueCycle)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 cycle, got %v", issues)
+	}
+
+	if issues[0].Path != "root/arm/hand/arm" {
+		t.Errorf("Unexpected cycle path %s", issues[0].Path)
+	}
+}
+
+// TestValidateDuplicateName 测试重复的节点名
+func TestValidateDuplicateName(t *testing.T) {
+	scene := buildTestHierarchy()
+	scene.RootNode.Children[1].Name = "hand"
+
+	issues := issuesOfKind(scene.Validate(), IssueDuplicateName)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 duplicate name, got %v", issues)
+	}
+
+	if issues[0].Path != "root/hand" {
+		t.Errorf("Expected the second use to be reported, got %s", issues[0].Path)
+	}
+}
+
+// TestValidateParentMismatch 测试父子关系不一致
+func TestValidateParentMismatch(t *testing.T) {
+	scene := buildTestHierarchy()
+	arm := scene.RootNode.Children[0]
+	legs := scene.RootNode.Children[1]
+
+	arm.Children[0].Parent = legs
+	arm.Children = append(arm.Children, legs)
+	scene.RootNode.Children = append(scene.RootNode.Children, nil)
+
+	issues := scene.Validate()
+
+	// hand和legs的Parent不一致, 且legs被引用了两次
+	if got := issuesOfKind(issues, IssueParentMismatch); len(got) != 3 {
+		t.Errorf("Expected 3 parent mismatches, got %v", got)
+	}
+
+	if got := issuesOfKind(issues, IssueNilNode); len(got) != 1 {
+		t.Errorf("Expected 1 nil node, got %v", got)
+	}
+}
+
+// TestImportHierarchy 测试多层级文件的节点树解析
+func TestImportHierarchy(t *testing.T) {
+	for _, file := range []string{"hierarchy.gltf", "hierarchy.dae"} {
+		t.Run(file, func(t *testing.T) {
+			scene, release, err := ImportFile(filepath.Join("testdata", file), PostProcessTriangulate)
+			if err != nil {
+				t.Fatalf("Unexpected import error: %v", err)
+			}
+			defer release()
+
+			if issues := scene.Validate(); issues != nil {
+				t.Errorf("Expected a valid hierarchy, got %v", issues)
+			}
+
+			counts := map[string]int{"arm": 1, "hand": 2, "legs": 4, "finger_a": 0, "leg_3": 0}
+			for name, want := range counts {
+				n := scene.FindNode(name)
+				if n == nil {
+					t.Fatalf("Expected node %s", name)
+				}
+				if len(n.Children) != want {
+					t.Errorf("Expected %s to have %d children, got %d", name, want, len(n.Children))
+				}
+			}
+
+			hand := scene.FindNode("hand")
+			if hand.Parent == nil || hand.Parent.Name != "arm" {
+				t.Error("Expected hand to be a child of arm")
+			}
+			if hand.Transformation[3][1] != 2 {
+				t.Errorf("Expected hand to be translated by 2 on y, got %v", hand.Transformation)
+			}
+
+			fingerB := scene.FindNode("finger_b")
+			if len(fingerB.MeshIndicies) != 1 || fingerB.Parent != hand {
+				t.Errorf("Expected finger_b to reference one mesh, got %v", fingerB.MeshIndicies)
+			}
+
+			head := scene.FindNode("head")
+			if len(head.MeshIndicies) != 1 {
+				t.Errorf("Expected head to reference one mesh, got %v", head.MeshIndicies)
+			}
+		})
+	}
+}
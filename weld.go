@@ -0,0 +1,322 @@
+package assimp
+
+import (
+	"math"
+
+	"github.com/flywave/go3d/vec3"
+	"github.com/flywave/go3d/vec4"
+)
+
+// WeldOptions controls which vertices Mesh.Weld merges. The zero value
+// merges vertices at exactly the same position.
+type WeldOptions struct {
+	// Epsilon is the largest distance between the positions of two merged
+	// vertices. It also applies to the positions of the anim meshes, so
+	// morph targets stay intact.
+	Epsilon float32
+
+	// CompareNormals only merges vertices whose normals differ by at most
+	// NormalAngle radians.
+	CompareNormals bool
+	NormalAngle    float32
+
+	// CompareTexCoords only merges vertices whose texture coordinates differ
+	// by at most TexCoordEpsilon in every component of every channel.
+	CompareTexCoords bool
+	TexCoordEpsilon  float32
+
+	// CompareColors only merges vertices whose colors differ by at most
+	// ColorEpsilon in every component of every color set.
+	CompareColors bool
+	ColorEpsilon  float32
+}
+
+// Weld merges vertices that match according to opts and returns how many
+// vertices were removed. A merged vertex keeps the attributes of the first
+// vertex of its group. Faces, bone weights and anim meshes are remapped to
+// the remaining vertices; faces that collapse are kept.
+//
+// Welding uses a spatial hash with cells a few epsilons wide, or wider for
+// meshes whose extent would need too many cells, so each vertex is only
+// compared with the kept vertices near it. The vertex data of a zero-copy
+// mesh is copied into Go memory.
+func (m *Mesh) Weld(opts WeldOptions) int {
+
+	n := len(m.Vertices)
+	if n == 0 {
+		return 0
+	}
+
+	cosAngle := float32(math.Cos(float64(opts.NormalAngle)))
+	compareNormals := opts.CompareNormals && len(m.Normals) >= n
+
+	// matches compares the attributes other than the position, which the
+	// grid has already checked.
+	matches := func(a, b int) bool {
+		if compareNormals && !withinAngle(m.Normals[a], m.Normals[b], cosAngle) {
+			return false
+		}
+		if opts.CompareTexCoords {
+			for _, uv := range m.TexCoords {
+				if len(uv) >= n && !withinVec3(uv[a], uv[b], opts.TexCoordEpsilon) {
+					return false
+				}
+			}
+		}
+		if opts.CompareColors {
+			for _, c := range m.ColorSets {
+				if len(c) >= n && !withinVec4(c[a], c[b], opts.ColorEpsilon) {
+					return false
+				}
+			}
+		}
+		for _, am := range m.AnimMeshes {
+			if len(am.Vertices) >= n && !withinDistance(am.Vertices[a], am.Vertices[b], opts.Epsilon) {
+				return false
+			}
+		}
+		return true
+	}
+
+	grid := newWeldGrid(m.Vertices, opts.Epsilon)
+
+	remap := make([]uint32, n)
+	var kept []int
+	for i, p := range m.Vertices {
+
+		j, found := grid.find(p, func(k int32) bool { return matches(i, kept[k]) })
+		if found {
+			remap[i] = uint32(j)
+			continue
+		}
+
+		remap[i] = uint32(len(kept))
+		grid.insert(p, int32(len(kept)))
+		kept = append(kept, i)
+	}
+
+	removed := n - len(kept)
+	if removed == 0 {
+		return 0
+	}
+
+	m.Vertices = gatherVertices(m.Vertices, kept)
+	m.Normals = gatherVertices(m.Normals, kept)
+	m.Tangents = gatherVertices(m.Tangents, kept)
+	m.BitTangents = gatherVertices(m.BitTangents, kept)
+	for i := range m.ColorSets {
+		m.ColorSets[i] = gatherVertices(m.ColorSets[i], kept)
+	}
+	for i := range m.TexCoords {
+		m.TexCoords[i] = gatherVertices(m.TexCoords[i], kept)
+	}
+	m.owner = nil
+
+	for _, am := range m.AnimMeshes {
+		am.Vertices = gatherVertices(am.Vertices, kept)
+		am.Normals = gatherVertices(am.Normals, kept)
+		am.Tangents = gatherVertices(am.Tangents, kept)
+		am.BitTangents = gatherVertices(am.BitTangents, kept)
+		for i := range am.Colors {
+			am.Colors[i] = gatherVertices(am.Colors[i], kept)
+		}
+		for i := range am.TexCoords {
+			am.TexCoords[i] = gatherVertices(am.TexCoords[i], kept)
+		}
+	}
+
+	for _, f := range m.Faces {
+		for i, idx := range f.Indices {
+			f.Indices[i] = uint(remap[idx])
+		}
+	}
+	for i, idx := range m.Indices {
+		m.Indices[i] = remap[idx]
+	}
+
+	// A bone keeps one weight per remaining vertex, the first one found.
+	seen := make([]int, len(kept))
+	for b, bone := range m.Bones {
+		weights := bone.Weights[:0]
+		for _, w := range bone.Weights {
+			if int(w.VertIndex) >= n {
+				continue
+			}
+			v := remap[w.VertIndex]
+			if seen[v] == b+1 {
+				continue
+			}
+			seen[v] = b + 1
+			weights = append(weights, VertexWeight{VertIndex: uint(v), Weight: w.Weight})
+		}
+		bone.Weights = weights
+	}
+
+	return removed
+}
+
+// weldGrid is a spatial hash of the vertices kept by Weld. Its cells are a
+// few epsilons wide, so the matches of a point are found in the one to eight
+// cells its epsilon box overlaps.
+type weldGrid struct {
+	origin  vec3.T
+	cell    float32
+	epsilon float32
+	// last is the key of the last cell along each axis.
+	last [3]int32
+	// head maps a cell to its last inserted vertex, next links each vertex
+	// to the one inserted into the same cell before it, or -1.
+	head   map[uint64]int32
+	next   []int32
+	points []vec3.T
+}
+
+// weldGridCells is the most cells along an axis of the bounding box. It
+// keeps the cell keys in range for an epsilon that is 0 or tiny compared to
+// the mesh.
+const weldGridCells = 1 << 20
+
+// weldGridCellSize is the width of the cells in epsilons. Wider cells hold
+// more vertices, narrower ones make more epsilon boxes span several cells.
+const weldGridCellSize = 4
+
+func newWeldGrid(points []vec3.T, epsilon float32) *weldGrid {
+
+	if !(epsilon > 0) {
+		epsilon = 0
+	}
+
+	box, _ := pointsAABB(slicePoints(points))
+	extent := max(box.Max[0]-box.Min[0], box.Max[1]-box.Min[1], box.Max[2]-box.Min[2])
+
+	cell := max(weldGridCellSize*epsilon, extent/weldGridCells)
+	if math.IsInf(float64(cell), 0) {
+		// An infinite epsilon puts all vertices into one cell.
+		cell = extent
+	}
+	if !(cell > 0) || math.IsInf(float64(cell), 0) {
+		cell = 1
+	}
+
+	g := &weldGrid{
+		origin:  box.Min,
+		cell:    cell,
+		epsilon: epsilon,
+		head:    make(map[uint64]int32),
+	}
+	// The keys inside the box are at most weldGridCells, clamp to that
+	// while computing the last one.
+	g.last = [3]int32{weldGridCells, weldGridCells, weldGridCells}
+	g.last = g.key(box.Max)
+
+	return g
+}
+
+// key returns the cell of p, clamped to the cells of the bounding box.
+func (g *weldGrid) key(p vec3.T) [3]int32 {
+	var k [3]int32
+	for i := range k {
+		f := math.Floor(float64((p[i] - g.origin[i]) / g.cell))
+		k[i] = int32(min(max(f, 0), float64(g.last[i])))
+	}
+	return k
+}
+
+// cellID packs a cell key, whose components are at most weldGridCells, into
+// a single map key.
+func cellID(k [3]int32) uint64 {
+	return uint64(k[0]) | uint64(k[1])<<21 | uint64(k[2])<<42
+}
+
+func (g *weldGrid) insert(p vec3.T, v int32) {
+
+	k := cellID(g.key(p))
+
+	head, ok := g.head[k]
+	if !ok {
+		head = -1
+	}
+
+	g.next = append(g.next, head)
+	g.points = append(g.points, p)
+	g.head[k] = v
+}
+
+// find returns the first inserted vertex within epsilon of p that match
+// accepts.
+func (g *weldGrid) find(p vec3.T, match func(v int32) bool) (int32, bool) {
+
+	e := vec3.T{g.epsilon, g.epsilon, g.epsilon}
+	lo, hi := g.key(vec3.Sub(&p, &e)), g.key(vec3.Add(&p, &e))
+
+	best := int32(-1)
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				v, ok := g.head[cellID([3]int32{x, y, z})]
+				if !ok {
+					continue
+				}
+				for ; v >= 0; v = g.next[v] {
+					if best >= 0 && v >= best {
+						continue
+					}
+					if withinDistance(g.points[v], p, g.epsilon) && match(v) {
+						best = v
+					}
+				}
+			}
+		}
+	}
+
+	return best, best >= 0
+}
+
+// gatherVertices returns the elements of s at the indices in kept, or nil if
+// s doesn't have an element for every vertex.
+func gatherVertices[T any](s []T, kept []int) []T {
+
+	if len(s) == 0 || len(s) <= kept[len(kept)-1] {
+		return nil
+	}
+
+	out := make([]T, len(kept))
+	for i, k := range kept {
+		out[i] = s[k]
+	}
+
+	return out
+}
+
+func withinDistance(a, b vec3.T, epsilon float32) bool {
+	d := vec3.Sub(&a, &b)
+	return d.LengthSqr() <= epsilon*epsilon
+}
+
+func withinAngle(a, b vec3.T, cosAngle float32) bool {
+
+	la, lb := a.Length(), b.Length()
+	if la == 0 || lb == 0 {
+		return a == b
+	}
+
+	return vec3.Dot(&a, &b) >= cosAngle*la*lb
+}
+
+func withinVec3(a, b vec3.T, epsilon float32) bool {
+	for i := range a {
+		if absf(a[i]-b[i]) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func withinVec4(a, b vec4.T, epsilon float32) bool {
+	for i := range a {
+		if absf(a[i]-b[i]) > epsilon {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+package assimp
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/flywave/go3d/vec3"
+)
+
+// buildSplitQuad 构建由两个三角形组成的四边形, 共享边上的顶点是重复的
+func buildSplitQuad() *Mesh {
+	return &Mesh{
+		Vertices: []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		Normals:  []vec3.T{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+		Faces:    []Face{{Indices: []uint{0, 1, 2}}, {Indices: []uint{3, 4, 5}}},
+	}
+}
+
+// TestMeshWeld 测试合并重复顶点并重映射面
+func TestMeshWeld(t *testing.T) {
+	mesh := buildSplitQuad()
+
+	if removed := mesh.Weld(WeldOptions{}); removed != 2 {
+		t.Fatalf("Expected 2 vertices to be removed, got %d", removed)
+	}
+	if len(mesh.Vertices) != 4 || len(mesh.Normals) != 4 {
+		t.Fatalf("Expected 4 vertices and normals, got %d and %d", len(mesh.Vertices), len(mesh.Normals))
+	}
+
+	want := [][]uint{{0, 1, 2}, {0, 2, 3}}
+	for i, f := range mesh.Faces {
+		for j, idx := range f.Indices {
+			if idx != want[i][j] {
+				t.Errorf("Expected faces %v, got %v", want, mesh.Faces)
+			}
+		}
+	}
+
+	if removed := mesh.Weld(WeldOptions{}); removed != 0 {
+		t.Errorf("Expected a welded mesh to stay unchanged, got %d removed", removed)
+	}
+}
+
+// TestMeshWeldThresholds 测试位置、法线、UV和颜色的容差
+func TestMeshWeldThresholds(t *testing.T) {
+	mesh := buildSplitQuad()
+	mesh.Vertices[3] = vec3.T{1e-4, 0, 0}
+	if removed := mesh.Weld(WeldOptions{}); removed != 1 {
+		t.Errorf("Expected only the exact duplicate to be removed, got %d", removed)
+	}
+
+	mesh = buildSplitQuad()
+	mesh.Vertices[3] = vec3.T{1e-4, 0, 0}
+	if removed := mesh.Weld(WeldOptions{Epsilon: 1e-3}); removed != 2 || mesh.Vertices[0] != (vec3.T{0, 0, 0}) {
+		t.Errorf("Expected the near duplicate to merge into the first vertex, got %d removed", removed)
+	}
+
+	// 硬边: 法线夹角为90度
+	mesh = buildSplitQuad()
+	mesh.Normals[3] = vec3.T{1, 0, 0}
+	if removed := mesh.Weld(WeldOptions{CompareNormals: true, NormalAngle: math.Pi / 6}); removed != 1 {
+		t.Errorf("Expected the hard edge to be kept, got %d removed", removed)
+	}
+	mesh = buildSplitQuad()
+	mesh.Normals[3] = vec3.T{1, 0, 0}
+	if removed := mesh.Weld(WeldOptions{CompareNormals: true, NormalAngle: math.Pi * 0.6}); removed != 2 {
+		t.Errorf("Expected normals within the angle to merge, got %d removed", removed)
+	}
+
+	// UV接缝
+	mesh = buildSplitQuad()
+	mesh.TexCoords[0] = []vec3.T{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0.5, 0, 0}, {1, 1, 0}, {0, 1, 0}}
+	if removed := mesh.Weld(WeldOptions{CompareTexCoords: true, TexCoordEpsilon: 0.01}); removed != 1 {
+		t.Errorf("Expected the UV seam to be kept, got %d removed", removed)
+	}
+	if len(mesh.TexCoords[0]) != 5 {
+		t.Errorf("Expected 5 texture coordinates, got %d", len(mesh.TexCoords[0]))
+	}
+}
+
+// TestMeshWeldRemapsBonesAndAnimMeshes 测试骨骼权重和变形目标的重映射
+func TestMeshWeldRemapsBonesAndAnimMeshes(t *testing.T) {
+	mesh := buildSplitQuad()
+	mesh.Bones = []*Bone{{Name: "bone", Weights: []VertexWeight{
+		{VertIndex: 2, Weight: 0.5}, {VertIndex: 4, Weight: 0.5}, {VertIndex: 5, Weight: 1},
+	}}}
+	// 变形目标中顶点0和3的位置不同, 不能合并
+	mesh.AnimMeshes = []*AnimMesh{{Name: "morph", Vertices: []vec3.T{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 1}, {0, 0, 1}, {1, 1, 1}, {0, 1, 0},
+	}}}
+
+	if removed := mesh.Weld(WeldOptions{}); removed != 1 {
+		t.Fatalf("Expected 1 vertex to be removed, got %d", removed)
+	}
+
+	if len(mesh.AnimMeshes[0].Vertices) != 5 || mesh.AnimMeshes[0].Vertices[3] != (vec3.T{0, 0, 1}) {
+		t.Errorf("Expected the anim mesh to be remapped, got %v", mesh.AnimMeshes[0].Vertices)
+	}
+
+	weights := mesh.Bones[0].Weights
+	if len(weights) != 2 || weights[0].VertIndex != 2 || weights[1].VertIndex != 4 || weights[1].Weight != 1 {
+		t.Errorf("Expected weights for vertices 2 and 4, got %v", weights)
+	}
+
+	if f := mesh.Faces[1].Indices; f[0] != 3 || f[1] != 2 || f[2] != 4 {
+		t.Errorf("Expected second face (3,2,4), got %v", f)
+	}
+}
+
+// TestMeshWeldFlatFaces 测试零拷贝网格的扁平索引缓冲
+func TestMeshWeldFlatFaces(t *testing.T) {
+	mesh := buildSplitQuad()
+	mesh.Faces = nil
+	mesh.Indices = []uint32{0, 1, 2, 3, 4, 5}
+	mesh.FaceOffsets = []uint32{0, 3, 6}
+
+	mesh.Weld(WeldOptions{})
+
+	want := []uint32{0, 1, 2, 0, 2, 3}
+	for i, idx := range mesh.Indices {
+		if idx != want[i] {
+			t.Fatalf("Expected indices %v, got %v", want, mesh.Indices)
+		}
+	}
+}
+
+// buildQuadGrid 构建size*size个四边形的平面网格, 每个四边形有自己的4个顶点
+func buildQuadGrid(size int) *Mesh {
+	mesh := &Mesh{}
+	for x := range size {
+		for y := range size {
+			base := uint(len(mesh.Vertices))
+			for _, d := range [][2]int{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+				// 加入小于容差的噪声
+				noise := float32((x*7+y*13+d[0]*5+d[1]*3)%10) * 1e-5
+				mesh.Vertices = append(mesh.Vertices, vec3.T{float32(x+d[0]) + noise, float32(y+d[1]) - noise, noise})
+			}
+			mesh.Faces = append(mesh.Faces, Face{Indices: []uint{base, base + 1, base + 2, base + 3}})
+		}
+	}
+	return mesh
+}
+
+// TestMeshWeldGrid 测试大网格的焊接
+func TestMeshWeldGrid(t *testing.T) {
+	const size = 200
+
+	mesh := buildQuadGrid(size)
+
+	removed := mesh.Weld(WeldOptions{Epsilon: 1e-3})
+	if want := 4*size*size - (size+1)*(size+1); removed != want {
+		t.Errorf("Expected %d vertices to be removed, got %d", want, removed)
+	}
+
+	// 容差为0时只合并完全相同的位置
+	mesh = buildQuadGrid(size)
+	if removed := mesh.Weld(WeldOptions{}); removed >= 4*size*size-(size+1)*(size+1) {
+		t.Errorf("Expected noisy vertices to stay apart without a tolerance, got %d removed", removed)
+	}
+
+	// 容差远大于网格时所有顶点合并为一个
+	for _, eps := range []float32{1e30, float32(math.Inf(1))} {
+		mesh = buildSplitQuad()
+		if removed := mesh.Weld(WeldOptions{Epsilon: eps}); removed != 5 {
+			t.Errorf("Expected all vertices to be merged for epsilon %v, got %d removed", eps, removed)
+		}
+	}
+}
+
+// BenchmarkMeshWeld 对1000*1000个四边形的大平面网格进行焊接
+func BenchmarkMeshWeld(b *testing.B) {
+	const size = 1000
+
+	src := buildQuadGrid(size)
+
+	b.ResetTimer()
+	for range b.N {
+		b.StopTimer()
+		mesh := &Mesh{Vertices: slices.Clone(src.Vertices), Faces: make([]Face, len(src.Faces))}
+		for i, f := range src.Faces {
+			mesh.Faces[i] = Face{Indices: slices.Clone(f.Indices)}
+		}
+		b.StartTimer()
+
+		mesh.Weld(WeldOptions{Epsilon: 1e-3})
+	}
+}